@@ -0,0 +1,105 @@
+// Package triangles is an example modified from OpenGL Programming Guide
+// (Eighth Edition).
+package triangles
+
+import (
+	"embed"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/glfw/v3.1/glfw"
+
+	"github.com/hurricanerix/gorb/util"
+)
+
+// assets embedded in the binary.
+//
+//go:embed *.vert *.frag
+var assets embed.FS
+
+const ( // Program IDs
+	trianglesProgID = iota
+	numPrograms     = iota
+)
+
+// vertex is laid out to match the attributes of triangles.vert.
+type vertex struct {
+	Pos [2]float32 `vertex:"location=0"`
+}
+
+// triangles implements util.App.
+type triangles struct {
+	programs [numPrograms]*util.Program
+	mesh     *util.Mesh
+}
+
+func init() {
+	util.Register(util.Example{
+		Chapter:     1,
+		Name:        "triangles",
+		Description: "Render two blue triangles.",
+		New:         func() util.App { return &triangles{} },
+		Options:     util.Options{Name: "Ch1-Triangles", Width: 512, Height: 512, Assets: assets},
+	})
+}
+
+// Init the program and the model to be rendered.
+func (t *triangles) Init() error {
+	var err error
+
+	// Load the GLSL program
+	shaders := []util.ShaderInfo{
+		util.ShaderInfo{Type: gl.VERTEX_SHADER, Filename: "triangles.vert"},
+		util.ShaderInfo{Type: gl.FRAGMENT_SHADER, Filename: "triangles.frag"},
+	}
+	t.programs[trianglesProgID], err = util.LoadReloadable(shaders)
+	if err != nil {
+		return err
+	}
+	t.programs[trianglesProgID].Use()
+
+	// Setup model to be rendered
+	vertices := []float32{
+		-0.90, -0.90, // Triangle 1
+		0.85, -0.90,
+		-0.90, 0.85,
+		0.90, -0.85, // Triangle 2
+		0.90, 0.90,
+		-0.85, 0.90,
+	}
+	t.mesh, err = util.NewMeshSeparate(gl.TRIANGLES, vertex{}, []interface{}{vertices}, nil)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Update does nothing, the triangles do not move.
+func (t *triangles) Update(dt float64) {}
+
+// Render the triangles.
+func (t *triangles) Render() {
+	// Clear buffer
+	gl.Clear(gl.COLOR_BUFFER_BIT)
+
+	// Render
+	t.mesh.Draw()
+}
+
+// Resize does nothing, the window is not resizable.
+func (t *triangles) Resize(width, height int) {}
+
+// Key does nothing, there are no controls.
+func (t *triangles) Key(key glfw.Key, action glfw.Action, mods glfw.ModifierKey) {}
+
+// Close deletes the GL objects created by Init.
+func (t *triangles) Close() {
+	if t.mesh != nil {
+		t.mesh.Delete()
+	}
+	for _, prog := range t.programs {
+		if prog != nil {
+			prog.Delete()
+		}
+	}
+}
@@ -0,0 +1,179 @@
+// Package drawcommands is an example modified from OpenGL Programming Guide
+// (Eighth Edition).
+package drawcommands
+
+import (
+	"embed"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/glfw/v3.1/glfw"
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/hurricanerix/gorb/util"
+	"github.com/hurricanerix/gorb/util/camera"
+)
+
+// assets embedded in the binary.
+//
+//go:embed *.vert *.frag
+var assets embed.FS
+
+const ( // Program IDs
+	primRestartProgID = iota
+	numPrograms       = iota
+)
+
+// vertex is laid out to match the attributes of the vertex shader.
+type vertex struct {
+	Pos   [4]float32 `vertex:"location=0"`
+	Color [4]float32 `vertex:"location=1"`
+}
+
+// matrices is the Matrices uniform block.
+type matrices struct {
+	modelMatrix      mgl32.Mat4
+	projectionMatrix mgl32.Mat4
+}
+
+// drawCommands implements util.App.
+type drawCommands struct {
+	programs [numPrograms]*util.Program
+	mesh     *util.Mesh
+	ubo      *util.UniformBuffer
+
+	camera   *camera.Camera
+	matrices matrices
+}
+
+func init() {
+	util.Register(util.Example{
+		Chapter:     3,
+		Name:        "drawcommands",
+		Description: "Draw a triangle with each of the draw commands.",
+		New:         func() util.App { return &drawCommands{} },
+		Options: util.Options{
+			Name: "Ch3-DrawCommands", Width: 512, Height: 512, Assets: assets,
+			Window: util.WindowOptions{Resizable: true},
+		},
+	})
+}
+
+// Init the program and the model to be rendered.
+func (d *drawCommands) Init() error {
+	var err error
+
+	// Load the GLSL program
+	shaders := []util.ShaderInfo{
+		util.ShaderInfo{Type: gl.VERTEX_SHADER, Filename: "draw_commands.vert"},
+		util.ShaderInfo{Type: gl.FRAGMENT_SHADER, Filename: "draw_commands.frag"},
+	}
+	d.programs[primRestartProgID], err = util.LoadReloadable(shaders)
+	if err != nil {
+		return err
+	}
+	d.programs[primRestartProgID].Use()
+
+	d.ubo, err = util.NewUniformBuffer("Matrices", util.Std140, &d.matrices)
+	if err != nil {
+		return err
+	}
+	if err := d.ubo.Attach(d.programs[primRestartProgID]); err != nil {
+		return err
+	}
+
+	// Setup model to be rendered
+	vertexPositions := []float32{
+		-1.0, -1.0, 0.0, 1.0,
+		1.0, -1.0, 0.0, 1.0,
+		-1.0, 1.0, 0.0, 1.0,
+		-1.0, -1.0, 0.0, 1.0,
+	}
+
+	vertexColors := []float32{
+		1.0, 1.0, 1.0, 1.0,
+		1.0, 1.0, 0.0, 1.0,
+		1.0, 0.0, 1.0, 1.0,
+		0.0, 1.0, 1.0, 1.0,
+	}
+
+	vertexIndices := []uint16{
+		0, 1, 2,
+	}
+
+	d.mesh, err = util.NewMeshSeparate(gl.TRIANGLES, vertex{}, []interface{}{vertexPositions, vertexColors}, vertexIndices)
+	if err != nil {
+		return err
+	}
+
+	gl.ClearColor(0.0, 0.0, 0.0, 1.0)
+
+	// Orbit the row of triangles from 5 units away
+	d.camera = camera.New(camera.Orbit)
+	d.camera.Attach(util.CurrentWindow())
+	d.camera.BindKeys("drawcommands.camera")
+
+	return nil
+}
+
+// Update moves the camera, the triangles do not move.
+func (d *drawCommands) Update(dt float64) {
+	d.camera.Update(dt)
+}
+
+// Render a triangle with each of the draw commands.
+func (d *drawCommands) Render() {
+	// Clear buffer
+	gl.Enable(gl.CULL_FACE)
+	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
+	gl.Disable(gl.DEPTH_TEST)
+	// TODO: figure out why enabling this does not work
+	//gl.UseProgram(RenderProg)
+
+	d.matrices.projectionMatrix = d.camera.Projection()
+
+	// Render
+	d.setModelMatrix(mgl32.Translate3D(-3, 0, 0))
+	d.mesh.DrawArrays(0, 3)
+
+	// DrawElements
+	d.setModelMatrix(mgl32.Translate3D(-1, 0, 0))
+	d.mesh.Draw()
+
+	// DrawElementsBaseVertex
+	d.setModelMatrix(mgl32.Translate3D(1, 0, 0))
+	d.mesh.DrawBaseVertex(1)
+
+	// DrawArraysInstanced
+	d.setModelMatrix(mgl32.Translate3D(3, 0, 0))
+	d.mesh.DrawArraysInstanced(0, 3, 1)
+}
+
+// setModelMatrix uploads the matrices with a new model matrix, viewed by the
+// camera.  Uploading the block replaces setting each mat4 with its own
+// glUniformMatrix4fv call.
+func (d *drawCommands) setModelMatrix(m mgl32.Mat4) {
+	d.matrices.modelMatrix = d.camera.View().Mul4(m)
+	d.ubo.Set(&d.matrices)
+}
+
+// Resize updates the camera's aspect ratio.
+func (d *drawCommands) Resize(width, height int) {
+	d.camera.Resize(width, height)
+}
+
+// Key does nothing, there are no controls.
+func (d *drawCommands) Key(key glfw.Key, action glfw.Action, mods glfw.ModifierKey) {}
+
+// Close deletes the GL objects created by Init.
+func (d *drawCommands) Close() {
+	if d.ubo != nil {
+		d.ubo.Delete()
+	}
+	if d.mesh != nil {
+		d.mesh.Delete()
+	}
+	for _, prog := range d.programs {
+		if prog != nil {
+			prog.Delete()
+		}
+	}
+}
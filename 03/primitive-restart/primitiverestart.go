@@ -0,0 +1,180 @@
+// Package primitiverestart is an example modified from OpenGL Programming Guide
+// (Eighth Edition).
+package primitiverestart
+
+import (
+	"embed"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/glfw/v3.1/glfw"
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/hurricanerix/gorb/util"
+	"github.com/hurricanerix/gorb/util/camera"
+)
+
+// assets embedded in the binary.
+//
+//go:embed *.vert *.frag
+var assets embed.FS
+
+const ( // Program IDs
+	primRestartProgID = iota
+	numPrograms       = iota
+)
+
+// rotationSpeed is how far rotation advances per second, the same as the
+// 0.00005 per frame it used to advance by at 60 frames per second.
+const rotationSpeed = 0.003
+
+// vertex is laid out to match the attributes of the vertex shader.
+type vertex struct {
+	Pos   [4]float32 `vertex:"location=0"`
+	Color [4]float32 `vertex:"location=1"`
+}
+
+// primitiveRestart implements util.App.
+type primitiveRestart struct {
+	programs [numPrograms]*util.Program
+	mesh     *util.Mesh
+
+	// App Settings
+	camera              *camera.Camera
+	modelMatrix         mgl32.Mat4
+	rotation            float32
+	usePrimitiveRestart bool
+}
+
+func init() {
+	util.Register(util.Example{
+		Chapter:     3,
+		Name:        "primitive-restart",
+		Description: "Draw a spinning cube as two triangle strips, with and without primitive restart.",
+		New:         func() util.App { return &primitiveRestart{} },
+		Options: util.Options{
+			Name: "Ch3-PrimitiveRestart", Width: 512, Height: 512, Assets: assets,
+			Window: util.WindowOptions{Resizable: true},
+		},
+	})
+}
+
+// Init the program and the model to be rendered.
+func (p *primitiveRestart) Init() error {
+	var err error
+
+	// Load the GLSL program
+	shaders := []util.ShaderInfo{
+		util.ShaderInfo{Type: gl.VERTEX_SHADER, Filename: "primitive_restart.vert"},
+		util.ShaderInfo{Type: gl.FRAGMENT_SHADER, Filename: "primitive_restart.frag"},
+	}
+	p.programs[primRestartProgID], err = util.LoadReloadable(shaders)
+	if err != nil {
+		return err
+	}
+	p.programs[primRestartProgID].Use()
+
+	// Setup model to be rendered
+	vertexPositions := []float32{
+		-1.0, -1.0, -1.0, 1.0,
+		-1.0, -1.0, 1.0, 1.0,
+		-1.0, 1.0, -1.0, 1.0,
+		-1.0, 1.0, 1.0, 1.0,
+		1.0, -1.0, -1.0, 1.0,
+		1.0, -1.0, 1.0, 1.0,
+		1.0, 1.0, -1.0, 1.0,
+		1.0, 1.0, 1.0, 1.0,
+	}
+	vertexColors := []float32{
+		1.0, 1.0, 1.0, 1.0,
+		1.0, 1.0, 0.0, 1.0,
+		1.0, 0.0, 1.0, 1.0,
+		1.0, 0.0, 0.0, 1.0,
+		0.0, 1.0, 1.0, 1.0,
+		0.0, 1.0, 0.0, 1.0,
+		0.0, 0.0, 1.0, 1.0,
+		0.5, 0.5, 0.5, 1.0,
+	}
+	vertexIndices := []uint16{
+		0, 1, 2, 3, 6, 7, 4, 5, // First strip
+		0xFFFF,                 // <<-- This is the restart index
+		2, 6, 0, 4, 1, 5, 3, 7, // Second strip
+	}
+	p.mesh, err = util.NewMeshSeparate(gl.TRIANGLE_STRIP, vertex{}, []interface{}{vertexPositions, vertexColors}, vertexIndices)
+	if err != nil {
+		return err
+	}
+
+	p.usePrimitiveRestart = true
+	util.BindKey("restart", glfw.KeyM, "Toggle primitive restart", func() {
+		p.usePrimitiveRestart = !p.usePrimitiveRestart
+	})
+	gl.ClearColor(0.05, 0.1, 0.05, 1.0)
+	p.rotation = 0
+
+	// Orbit the cube from 5 units away
+	p.camera = camera.New(camera.Orbit)
+	p.camera.Attach(util.CurrentWindow())
+	p.camera.BindKeys("primitive-restart.camera")
+
+	return nil
+}
+
+// Update the rotation of the cube.
+func (p *primitiveRestart) Update(dt float64) {
+	p.camera.Update(dt)
+	p.rotation += float32(dt) * rotationSpeed
+	//static float q = 0.0f;
+	//X := mgl32.Vec3{1, 0, 0}
+	Y := mgl32.Vec3{0, 1, 0}
+	Z := mgl32.Vec3{0, 0, 1}
+	// Set up the model matrix
+	p.modelMatrix = mgl32.HomogRotate3D(p.rotation*360, Y).Mul4(mgl32.HomogRotate3D(p.rotation*720, Z))
+}
+
+// Render the cube.
+func (p *primitiveRestart) Render() {
+	// Clear buffer
+	gl.Enable(gl.CULL_FACE)
+	gl.Disable(gl.DEPTH_TEST)
+	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
+	// Activate simple shading program
+	// TODO: figure out why enabling this does not work
+	//gl.UseProgram(RenderProg)
+
+	// Render
+	p.programs[primRestartProgID].SetMat4("modelMatrix", p.camera.View().Mul4(p.modelMatrix))
+	p.programs[primRestartProgID].SetMat4("projectionMatrix", p.camera.Projection())
+
+	if p.usePrimitiveRestart {
+		// When primitive restart is on, we can call one draw command
+		gl.ClearColor(0.05, 0.1, 0.05, 1.0)
+		gl.Enable(gl.PRIMITIVE_RESTART)
+		gl.PrimitiveRestartIndex(0xFFFF)
+		p.mesh.Draw()
+	} else {
+		gl.ClearColor(0.05, 0.05, 0.1, 1.0)
+		// Without primitive restart, we need to call two draw commands
+		gl.Disable(gl.PRIMITIVE_RESTART)
+		p.mesh.DrawRange(0, 8)
+		p.mesh.DrawRange(9, 8)
+	}
+}
+
+// Resize updates the camera's aspect ratio.
+func (p *primitiveRestart) Resize(width, height int) {
+	p.camera.Resize(width, height)
+}
+
+// Key does nothing, the controls are bound in Init.
+func (p *primitiveRestart) Key(key glfw.Key, action glfw.Action, mods glfw.ModifierKey) {}
+
+// Close deletes the GL objects created by Init.
+func (p *primitiveRestart) Close() {
+	if p.mesh != nil {
+		p.mesh.Delete()
+	}
+	for _, prog := range p.programs {
+		if prog != nil {
+			prog.Delete()
+		}
+	}
+}
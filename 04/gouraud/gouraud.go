@@ -0,0 +1,120 @@
+// Package gouraud is an example modified from OpenGL Programming Guide
+// (Eighth Edition).
+package gouraud
+
+import (
+	"embed"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/glfw/v3.1/glfw"
+	"github.com/hurricanerix/gorb/util"
+)
+
+// assets embedded in the binary.
+//
+//go:embed *.vert *.frag
+var assets embed.FS
+
+const ( // Program IDs
+	trianglesProgID = iota
+	numPrograms     = iota
+)
+
+// vertexData is laid out to match the attributes of gouraud.vert.
+type vertexData struct {
+	Pos   [2]float32 `vertex:"location=0"`
+	Color [4]uint8   `vertex:"location=1,normalized"`
+}
+
+// gouraud implements util.App.
+type gouraud struct {
+	programs [numPrograms]*util.Program
+	mesh     *util.Mesh
+
+	mode uint32
+}
+
+func init() {
+	util.Register(util.Example{
+		Chapter:     4,
+		Name:        "gouraud",
+		Description: "Shade two triangles by interpolating their vertex colors.",
+		New:         func() util.App { return &gouraud{} },
+		Options:     util.Options{Name: "Ch4-Gouraud", Width: 512, Height: 512, Assets: assets},
+	})
+}
+
+// Init the program and the model to be rendered.
+func (g *gouraud) Init() error {
+	var err error
+
+	// Load the GLSL program
+	shaders := []util.ShaderInfo{
+		util.ShaderInfo{Type: gl.VERTEX_SHADER, Filename: "gouraud.vert"},
+		util.ShaderInfo{Type: gl.FRAGMENT_SHADER, Filename: "gouraud.frag"},
+	}
+	g.programs[trianglesProgID], err = util.LoadReloadable(shaders)
+	if err != nil {
+		return err
+	}
+	g.programs[trianglesProgID].Use()
+
+	// Setup model to be rendered
+	vertices := []vertexData{
+		vertexData{Pos: [2]float32{-0.90, -0.90}, Color: [4]uint8{255, 0, 0, 255}}, // Triangle 1
+		vertexData{Pos: [2]float32{0.85, -0.90}, Color: [4]uint8{0, 255, 0, 255}},
+		vertexData{Pos: [2]float32{-0.90, 0.85}, Color: [4]uint8{0, 0, 255, 255}},
+		vertexData{Pos: [2]float32{0.90, -0.85}, Color: [4]uint8{10, 10, 10, 255}}, // Triangle 2
+		vertexData{Pos: [2]float32{0.90, 0.90}, Color: [4]uint8{100, 100, 100, 255}},
+		vertexData{Pos: [2]float32{-0.85, 0.90}, Color: [4]uint8{255, 255, 255, 255}},
+	}
+	g.mesh, err = util.NewMesh(gl.TRIANGLES, vertices, nil)
+	if err != nil {
+		return err
+	}
+
+	g.mode = gl.FILL
+	util.BindKey("wireframe", glfw.KeyM, "Toggle wireframe polygons", g.toggleWireframe)
+
+	return nil
+}
+
+// Update does nothing, the triangles do not move.
+func (g *gouraud) Update(dt float64) {}
+
+// Render the triangles.
+func (g *gouraud) Render() {
+	// Clear buffer
+	gl.Clear(gl.COLOR_BUFFER_BIT)
+
+	// Render
+	g.mesh.Draw()
+}
+
+// Resize does nothing, the window is not resizable.
+func (g *gouraud) Resize(width, height int) {}
+
+// Key does nothing, the controls are bound in Init.
+func (g *gouraud) Key(key glfw.Key, action glfw.Action, mods glfw.ModifierKey) {}
+
+// toggleWireframe switches between filled and wireframe polygons.
+func (g *gouraud) toggleWireframe() {
+	if g.mode == gl.FILL {
+		g.mode = gl.LINE
+	} else {
+		g.mode = gl.FILL
+	}
+	gl.PolygonMode(gl.FRONT_AND_BACK, g.mode)
+}
+
+// Close deletes the GL objects created by Init.
+func (g *gouraud) Close() {
+	if g.mesh != nil {
+		g.mesh.Delete()
+	}
+	for _, prog := range g.programs {
+		if prog != nil {
+			prog.Delete()
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+)
+
+// compare got against want, returning the number of pixels where any channel
+// differs by more than tolerance and an image highlighting them.  Matching
+// pixels are drawn as a faded copy of want, mismatches in solid red.
+func compare(got, want image.Image, tolerance int) (int, *image.NRGBA) {
+	bounds := want.Bounds()
+	diff := image.NewNRGBA(bounds)
+	if got.Bounds().Size() != bounds.Size() {
+		return bounds.Dx() * bounds.Dy(), diff
+	}
+
+	offset := got.Bounds().Min.Sub(bounds.Min)
+	bad := 0
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			g := color.NRGBAModel.Convert(got.At(x+offset.X, y+offset.Y)).(color.NRGBA)
+			w := color.NRGBAModel.Convert(want.At(x, y)).(color.NRGBA)
+			if channelDiff(g.R, w.R) > tolerance || channelDiff(g.G, w.G) > tolerance ||
+				channelDiff(g.B, w.B) > tolerance || channelDiff(g.A, w.A) > tolerance {
+				bad++
+				diff.SetNRGBA(x, y, color.NRGBA{R: 255, A: 255})
+				continue
+			}
+			grey := uint8((int(w.R) + int(w.G) + int(w.B)) / 3 / 4)
+			diff.SetNRGBA(x, y, color.NRGBA{R: grey, G: grey, B: grey, A: 255})
+		}
+	}
+	return bad, diff
+}
+
+// channelDiff returns the absolute difference between a and b.
+func channelDiff(a, b uint8) int {
+	if a > b {
+		return int(a - b)
+	}
+	return int(b - a)
+}
+
+// readPNG decodes the PNG in filename.
+func readPNG(filename string) (image.Image, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return png.Decode(f)
+}
+
+// writePNG encodes img to filename.
+func writePNG(filename string, img image.Image) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(f, img); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// solid returns a w×h image filled with c.
+func solid(w, h int, c color.NRGBA) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.SetNRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestCompare(t *testing.T) {
+	grey := color.NRGBA{R: 100, G: 100, B: 100, A: 255}
+	red := color.NRGBA{R: 255, A: 255}
+
+	// changed returns a grey image with the pixel at (1, 2) set to c.
+	changed := func(c color.NRGBA) *image.NRGBA {
+		img := solid(4, 4, grey)
+		img.SetNRGBA(1, 2, c)
+		return img
+	}
+
+	tests := []struct {
+		name string
+		got  image.Image
+		want image.Image
+		bad  int
+		// red is whether the changed pixel at (1, 2) is marked in the diff.
+		red bool
+	}{
+		{
+			name: "identical",
+			got:  solid(4, 4, grey),
+			want: solid(4, 4, grey),
+		},
+		{
+			name: "within tolerance",
+			got:  changed(color.NRGBA{R: 102, G: 98, B: 100, A: 255}),
+			want: solid(4, 4, grey),
+		},
+		{
+			name: "over tolerance",
+			got:  changed(color.NRGBA{R: 100, G: 103, B: 100, A: 255}),
+			want: solid(4, 4, grey),
+			bad:  1,
+			red:  true,
+		},
+		{
+			name: "alpha over tolerance",
+			got:  changed(color.NRGBA{R: 100, G: 100, B: 100, A: 0}),
+			want: solid(4, 4, grey),
+			bad:  1,
+			red:  true,
+		},
+		{
+			name: "size mismatch",
+			got:  solid(4, 3, grey),
+			want: solid(4, 4, grey),
+			bad:  16,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bad, diff := compare(tt.got, tt.want, 2)
+			if bad != tt.bad {
+				t.Errorf("bad = %d, want %d", bad, tt.bad)
+			}
+			if diff.Bounds() != tt.want.Bounds() {
+				t.Errorf("diff bounds = %v, want %v", diff.Bounds(), tt.want.Bounds())
+			}
+			if got := diff.NRGBAAt(1, 2) == red; got != tt.red {
+				t.Errorf("diff at (1, 2) = %v, want red %v", diff.NRGBAAt(1, 2), tt.red)
+			}
+		})
+	}
+}
+
+func TestCompareOffsetBounds(t *testing.T) {
+	want := solid(4, 4, color.NRGBA{G: 200, A: 255})
+	got := image.NewNRGBA(image.Rect(10, 10, 14, 14))
+	copy(got.Pix, want.Pix)
+	if bad, _ := compare(got, want, 0); bad != 0 {
+		t.Errorf("bad = %d, want 0", bad)
+	}
+}
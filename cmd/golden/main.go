@@ -0,0 +1,165 @@
+// Command golden renders every example headlessly and compares the last frame
+// against the golden.png checked in next to it.
+//
+// Run it from the root of the repository:
+//
+//	$ go run ./cmd/golden
+//	$ go run ./cmd/golden -update
+//
+// Each example is run for a fixed number of frames with a fixed time step, so
+// animated examples always stop at the same point.  When a frame does not
+// match, a golden.diff.png is written next to the reference with the
+// mismatched pixels in red.  Examples which need more than the machine's GL
+// supports are skipped rather than failed.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	update    = flag.Bool("update", false, "replace the reference images with the rendered frames")
+	tolerance = flag.Int("tolerance", 2, "largest per-channel difference (0-255) for a pixel to still match")
+	frames    = flag.Int("frames", 3, "frames to render before capturing")
+	timeStep  = flag.Float64("timestep", 1.0/60.0, "seconds each frame advances the example by")
+	run       = flag.String("run", "", "only check examples whose directory matches this regexp")
+	leaks     = flag.String("leaks", "fail", "GORB_LEAKS for the examples: fail, report, or empty to ignore leaked GL objects")
+)
+
+func main() {
+	flag.Parse()
+
+	filter, err := regexp.Compile(*run)
+	if err != nil {
+		fatalf("invalid -run: %s", err)
+	}
+
+	tmp, err := os.MkdirTemp("", "gorb-golden")
+	if err != nil {
+		fatalf("%s", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	gorb := filepath.Join(tmp, "gorb")
+	build := exec.Command("go", "build", "-o", gorb, "./cmd/gorb")
+	if out, err := build.CombinedOutput(); err != nil {
+		fatalf("building gorb failed, run from the root of the repository: %s\n%s", err, out)
+	}
+	out, err := exec.Command(gorb, "list").Output()
+	if err != nil {
+		fatalf("gorb list: %s", err)
+	}
+
+	failed := 0
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		id := strings.Fields(line)[0]
+		dir := exampleDir(id)
+		if !filter.MatchString(dir) {
+			continue
+		}
+		err := check(gorb, id, dir, tmp)
+		if skip, ok := err.(skipped); ok {
+			fmt.Printf("skip %s: %s\n", dir, skip.reason)
+			continue
+		}
+		if err != nil {
+			fmt.Printf("FAIL %s: %s\n", dir, err)
+			failed++
+			continue
+		}
+		fmt.Printf("ok   %s\n", dir)
+	}
+
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// skipStatus is the exit status of gorb run when the machine cannot run an
+// example.
+const skipStatus = 3
+
+// skipped is returned by check for an example the machine cannot run.
+type skipped struct {
+	reason string
+}
+
+func (s skipped) Error() string {
+	return s.reason
+}
+
+// lastLine returns the last line of out, which is where gorb run says why
+// it skipped an example.
+func lastLine(out []byte) string {
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	return lines[len(lines)-1]
+}
+
+// exampleDir returns the directory of the example with the given ID, such
+// as 03/primitive-restart for ch03/primitive-restart.
+func exampleDir(id string) string {
+	return filepath.FromSlash(strings.TrimPrefix(id, "ch"))
+}
+
+// check renders the example in dir and compares it against its reference.
+func check(gorb, id, dir, tmp string) error {
+	got := filepath.Join(tmp, strings.Replace(id, "/", "-", -1)+".png")
+
+	cmd := exec.Command(gorb, "run", id)
+	cmd.Env = append(os.Environ(),
+		"GORB_HEADLESS=1",
+		fmt.Sprintf("GORB_FRAMES=%d", *frames),
+		fmt.Sprintf("GORB_TIMESTEP=%g", *timeStep),
+		"GORB_CAPTURE="+got,
+		"GORB_LEAKS="+*leaks,
+	)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		if exit, ok := err.(*exec.ExitError); ok && exit.ExitCode() == skipStatus {
+			return skipped{reason: strings.TrimPrefix(lastLine(out), "skipped: ")}
+		}
+		return fmt.Errorf("run failed: %s\n%s", err, out)
+	}
+
+	gotImg, err := readPNG(got)
+	if err != nil {
+		return err
+	}
+
+	reference := filepath.Join(dir, "golden.png")
+	if *update {
+		return writePNG(reference, gotImg)
+	}
+
+	wantImg, err := readPNG(reference)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("no reference image, run with -update to create %s", reference)
+	} else if err != nil {
+		return err
+	}
+
+	diffFile := filepath.Join(dir, "golden.diff.png")
+	bad, diff := compare(gotImg, wantImg, *tolerance)
+	if bad == 0 {
+		os.Remove(diffFile)
+		return nil
+	}
+	if err := writePNG(diffFile, diff); err != nil {
+		return err
+	}
+	if gotImg.Bounds().Size() != wantImg.Bounds().Size() {
+		return fmt.Errorf("rendered %v, reference is %v", gotImg.Bounds().Size(), wantImg.Bounds().Size())
+	}
+	return fmt.Errorf("%d pixels differ by more than %d, see %s", bad, *tolerance, diffFile)
+}
+
+// fatalf prints the message and exits.
+func fatalf(format string, a ...interface{}) {
+	fmt.Fprintf(os.Stderr, "golden: "+format+"\n", a...)
+	os.Exit(2)
+}
@@ -0,0 +1,10 @@
+package main
+
+// Every example, each of which registers itself when imported.  Adding an
+// example only needs a line here.
+import (
+	_ "github.com/hurricanerix/gorb/01/triangles"
+	_ "github.com/hurricanerix/gorb/03/drawcommands"
+	_ "github.com/hurricanerix/gorb/03/primitive-restart"
+	_ "github.com/hurricanerix/gorb/04/gouraud"
+)
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/hurricanerix/gorb/util"
+)
+
+// info prints the capabilities of the newest context this machine can
+// create, as text or as JSON with -json.
+func info(args []string) error {
+	if rest := parseArgs(args); len(rest) > 0 {
+		return fmt.Errorf("unexpected arguments: %v", rest)
+	}
+
+	// Ask for the newest version, and let the fallbacks find the newest
+	// one which is available.
+	opts := util.WindowOptions{Title: "gorb info", Width: 1, Height: 1, Hidden: true, Version: util.GLVersion{Major: 4, Minor: 6}}
+	var caps util.Capabilities
+	if *headless {
+		h, err := util.NewHeadless(opts)
+		if err != nil {
+			return err
+		}
+		caps = util.QueryCapabilities()
+		h.Destroy()
+	} else {
+		w, err := util.NewWindow(opts)
+		if err != nil {
+			return err
+		}
+		caps = util.QueryCapabilities()
+		w.Destroy()
+		util.Terminate()
+	}
+
+	if *jsonOut {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(caps)
+	}
+	fmt.Print(caps)
+	return nil
+}
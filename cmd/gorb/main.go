@@ -0,0 +1,141 @@
+// Command gorb lists and runs the examples, and reports what the GL driver
+// supports.
+//
+//	$ go run ./cmd/gorb list
+//	$ go run ./cmd/gorb run ch03/primitive-restart
+//	$ go run ./cmd/gorb run -headless -frames 10 -screenshot cube.png ch03/primitive-restart
+//	$ go run ./cmd/gorb info -json
+//
+// Flags may come before or after the example.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+
+	"github.com/hurricanerix/gorb/util"
+)
+
+var (
+	size     = flag.String("size", "", "window size as WIDTHxHEIGHT, the example's own size if empty")
+	frames   = flag.Int("frames", 0, "frames to render before exiting, 0 runs until the window is closed")
+	headless = flag.Bool("headless", false, "render offscreen instead of opening a window")
+	jsonOut  = flag.Bool("json", false, "print gorb info as JSON")
+
+	screenshot      = flag.String("screenshot", "", "save the first frame, or the last if -frames is set, to this PNG file")
+	screenshotScale = flag.Int("screenshot-scale", 0, "render the screenshot at this many times the resolution and scale it down")
+	screenshotAlpha = flag.Bool("screenshot-alpha", false, "keep the alpha channel in the screenshot")
+
+	record       = flag.String("record", "", "record frames to a .gif, a numbered PNG sequence, or - for raw RGBA on standard output")
+	recordFrames = flag.Int("record-frames", 60, "frames to record")
+	recordFPS    = flag.Float64("record-fps", 30, "frame rate of the recording, which time advances at regardless of how fast frames render")
+)
+
+// skipStatus is the exit status when the example cannot run on this
+// machine, so harnesses can tell it apart from a failure.
+const skipStatus = 3
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("gorb: ")
+	flag.Usage = usage
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) == 0 {
+		usage()
+		os.Exit(2)
+	}
+	switch args[0] {
+	case "list":
+		list()
+	case "run":
+		err := run(args[1:])
+		if skip, ok := err.(*util.SkipError); ok {
+			fmt.Fprintln(os.Stderr, skip)
+			os.Exit(skipStatus)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+	case "info":
+		if err := info(args[1:]); err != nil {
+			log.Fatal(err)
+		}
+	default:
+		usage()
+		os.Exit(2)
+	}
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: gorb list\n       gorb run [flags] example\n       gorb info [-json] [-headless]\n\nflags:\n")
+	flag.PrintDefaults()
+}
+
+// list prints every example.
+func list() {
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	for _, e := range util.Examples() {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", e.ID(), e.Options.Requires, e.Description)
+	}
+	tw.Flush()
+}
+
+// parseArgs parses the flags in args, which may come before, between or
+// after the other arguments, and returns the other arguments.
+func parseArgs(args []string) []string {
+	var rest []string
+	for {
+		// The command line is set to exit on errors.
+		flag.CommandLine.Parse(args)
+		if flag.NArg() == 0 {
+			return rest
+		}
+		rest = append(rest, flag.Arg(0))
+		args = flag.Args()[1:]
+	}
+}
+
+// run the example named in args.
+func run(args []string) error {
+	rest := parseArgs(args)
+	if len(rest) != 1 {
+		return fmt.Errorf("run needs one example, see gorb list")
+	}
+	id := rest[0]
+
+	e, ok := util.LookupExample(id)
+	if !ok {
+		return fmt.Errorf("no example %q, see gorb list", id)
+	}
+
+	opts := e.Options
+	if *size != "" {
+		if _, err := fmt.Sscanf(*size, "%dx%d", &opts.Width, &opts.Height); err != nil {
+			return fmt.Errorf("invalid -size %q, want WIDTHxHEIGHT: %s", *size, err)
+		}
+	}
+	if *frames > 0 {
+		opts.Frames = *frames
+	}
+	if *headless {
+		opts.Headless = true
+	}
+	if *screenshot != "" {
+		opts.Capture = *screenshot
+	}
+	if *screenshotScale > 0 {
+		opts.CaptureScale = *screenshotScale
+	}
+	if *screenshotAlpha {
+		opts.CaptureAlpha = true
+	}
+	if *record != "" {
+		opts.Record = &util.RecordOptions{Path: *record, Frames: *recordFrames, FPS: *recordFPS}
+	}
+	return util.Run(e.New(), opts)
+}
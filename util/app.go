@@ -13,50 +13,64 @@ func init() {
 	runtime.LockOSThread()
 }
 
-// NewWindow context is returned.
-func NewWindow(name string, height, width int) (*glfw.Window, error) {
-	var window *glfw.Window
+// currentWindow is the window Run is driving, nil when headless.
+var currentWindow *glfw.Window
 
+// CurrentWindow returns the window Run opened for the App, or nil when
+// running headless, so input handlers can be attached to it in Init.
+func CurrentWindow() *glfw.Window {
+	return currentWindow
+}
+
+// NewWindow creates a window as described by opts, makes its context
+// current and returns it.
+func NewWindow(opts WindowOptions) (*glfw.Window, error) {
 	// NOTE: Using GLFW instead of GLUT
 	if err := glfw.Init(); err != nil {
 		return nil, fmt.Errorf("failed to initialize glfw: %s", err)
 	}
 
-	glfw.WindowHint(glfw.Resizable, glfw.False)
-	glfw.WindowHint(glfw.ContextVersionMajor, 4)
-	glfw.WindowHint(glfw.ContextVersionMinor, 1)
-	glfw.WindowHint(glfw.OpenGLProfile, glfw.OpenGLCoreProfile)
-	glfw.WindowHint(glfw.OpenGLForwardCompatible, glfw.True)
-
-	window, err := glfw.CreateWindow(width, height, name, nil, nil)
+	window, version, err := createWindow(opts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create window: %s", err)
+		glfw.Terminate()
+		return nil, err
+	}
+	if want := opts.versions()[0]; version != want {
+		logger.Printf("GL %s is not available, using %s\n", want, version)
 	}
 
 	window.MakeContextCurrent()
 
 	if err := gl.Init(); err != nil {
+		glfw.Terminate()
 		return nil, fmt.Errorf("unable to initialize Glow ... exiting: %s", err)
 	}
 
-	fmt.Println("OpenGL vendor", gl.GoStr(gl.GetString(gl.VENDOR)))
-	fmt.Println("OpenGL renderer", gl.GoStr(gl.GetString(gl.RENDERER)))
-	fmt.Println("OpenGL version", gl.GoStr(gl.GetString(gl.VERSION)))
-	fmt.Println("GLSL version", gl.GoStr(gl.GetString(gl.SHADING_LANGUAGE_VERSION)))
+	switch opts.SwapInterval {
+	case 0:
+		glfw.SwapInterval(1)
+	case Disabled:
+		glfw.SwapInterval(0)
+	default:
+		glfw.SwapInterval(opts.SwapInterval)
+	}
+	if opts.SRGB {
+		gl.Enable(gl.FRAMEBUFFER_SRGB)
+	}
 
+	bindDefaultKeys(window)
 	window.SetKeyCallback(keyCallback)
 
 	return window, nil
 }
 
-// Terminate glfw
+// Terminate glfw.  Key bindings are removed.
 func Terminate() {
+	resetKeys()
 	glfw.Terminate()
 }
 
-// keyCallback to quit the program.
+// keyCallback calls the actions bound with BindKey.
 func keyCallback(w *glfw.Window, key glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey) {
-	if action == glfw.Release && key == glfw.KeyEscape {
-		w.SetShouldClose(true)
-	}
+	handleKey(key, action)
 }
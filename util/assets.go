@@ -0,0 +1,63 @@
+package util
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+)
+
+// assets is the file system shaders and other assets are read from.  Run
+// sets it from Options.Assets and Options.AssetDir.
+var assets fs.FS = os.DirFS(".")
+
+// SetAssets changes the file system assets are read from.  Paths within it
+// use forward slashes, as with any fs.FS.
+func SetAssets(fsys fs.FS) {
+	assets = fsys
+}
+
+// ReadAsset returns the contents of the named asset.
+func ReadAsset(name string) ([]byte, error) {
+	return fs.ReadFile(assets, name)
+}
+
+// StatAsset returns a FileInfo describing the named asset.
+func StatAsset(name string) (fs.FileInfo, error) {
+	return fs.Stat(assets, name)
+}
+
+// AssetFS returns a file system which reads from dir when it is set, falling
+// back to embedded for anything not found there.  This lets binaries carry
+// their assets with an embed.FS, while a source directory can be used in
+// development to pick up edits without rebuilding.  If both are unset the
+// current directory is used.
+func AssetFS(embedded fs.FS, dir string) fs.FS {
+	var layers overlayFS
+	if dir != "" {
+		layers = append(layers, os.DirFS(dir))
+	}
+	if embedded != nil {
+		layers = append(layers, embedded)
+	}
+	if len(layers) == 0 {
+		return os.DirFS(".")
+	}
+	return layers
+}
+
+// overlayFS opens each file from the first layer that has it.
+type overlayFS []fs.FS
+
+// Open the named file from the first layer containing it.
+func (o overlayFS) Open(name string) (fs.File, error) {
+	for _, fsys := range o {
+		f, err := fsys.Open(name)
+		if err == nil {
+			return f, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+	}
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
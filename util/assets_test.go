@@ -0,0 +1,86 @@
+package util
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func TestAssetFS(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "shaders"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "shaders", "main.vert"), []byte("edited"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	embedded := fstest.MapFS{
+		"shaders/main.vert": {Data: []byte("embedded")},
+		"shaders/main.frag": {Data: []byte("embedded frag")},
+	}
+
+	tests := []struct {
+		name     string
+		embedded fs.FS
+		dir      string
+		file     string
+		want     string
+		// wantNotExist is whether file should not be found at all.
+		wantNotExist bool
+	}{
+		{
+			name:     "directory overrides embed",
+			embedded: embedded,
+			dir:      dir,
+			file:     "shaders/main.vert",
+			want:     "edited",
+		},
+		{
+			name:     "missing file falls back to embed",
+			embedded: embedded,
+			dir:      dir,
+			file:     "shaders/main.frag",
+			want:     "embedded frag",
+		},
+		{
+			name:     "embed only",
+			embedded: embedded,
+			file:     "shaders/main.vert",
+			want:     "embedded",
+		},
+		{
+			name: "directory only",
+			dir:  dir,
+			file: "shaders/main.vert",
+			want: "edited",
+		},
+		{
+			name:         "missing from both",
+			embedded:     embedded,
+			dir:          dir,
+			file:         "shaders/missing.glsl",
+			wantNotExist: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := fs.ReadFile(AssetFS(tt.embedded, tt.dir), tt.file)
+			if tt.wantNotExist {
+				if !errors.Is(err, fs.ErrNotExist) {
+					t.Fatalf("error = %v, want fs.ErrNotExist", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("%s = %q, want %q", tt.file, data, tt.want)
+			}
+		})
+	}
+}
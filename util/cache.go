@@ -0,0 +1,118 @@
+package util
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// cacheDir holds program binaries, caching is disabled when it is empty.
+var cacheDir string
+
+// SetProgramCache enables caching linked program binaries in dir, so later
+// runs can skip compiling shaders that have not changed.  An empty dir
+// disables the cache.  Run sets this from Options.ProgramCache.
+func SetProgramCache(dir string) {
+	cacheDir = dir
+}
+
+// cacheKey identifies a program built from shaders.  The driver is part of
+// the key because a binary is only valid for the driver which produced it.
+// An empty key is returned if caching is disabled or not supported.
+func cacheKey(shaders []ShaderInfo, separable bool) string {
+	if cacheDir == "" {
+		return ""
+	}
+	var formats int32
+	if gl.GetIntegerv(gl.NUM_PROGRAM_BINARY_FORMATS, &formats); formats == 0 {
+		return ""
+	}
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%s\x00%t\x00",
+		gl.GoStr(gl.GetString(gl.VENDOR)),
+		gl.GoStr(gl.GetString(gl.RENDERER)),
+		gl.GoStr(gl.GetString(gl.VERSION)),
+		separable)
+	for _, s := range shaders {
+		fmt.Fprintf(h, "%d\x00%d\x00%s", s.Type, len(s.source), s.source)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// cacheFile returns the name of the file the program for key is kept in.
+func cacheFile(key string) string {
+	return filepath.Join(cacheDir, key+".bin")
+}
+
+// loadCached creates a program from the binary cached for key.  If the
+// driver rejects the binary, for example after a driver update which did not
+// change the version string, it is removed and false is returned so the
+// program is built from source instead.
+func loadCached(key string, separable bool) (uint32, bool) {
+	if key == "" {
+		return 0, false
+	}
+	data, err := os.ReadFile(cacheFile(key))
+	if err != nil || len(data) <= 4 {
+		return 0, false
+	}
+	format := binary.LittleEndian.Uint32(data)
+	data = data[4:]
+
+	program := gl.CreateProgram()
+	if separable {
+		gl.ProgramParameteri(program, gl.PROGRAM_SEPARABLE, gl.TRUE)
+	}
+	gl.ProgramBinary(program, format, gl.Ptr(data), int32(len(data)))
+
+	var linked int32
+	if gl.GetProgramiv(program, gl.LINK_STATUS, &linked); linked == gl.FALSE {
+		gl.DeleteProgram(program)
+		os.Remove(cacheFile(key))
+		return 0, false
+	}
+	return program, true
+}
+
+// saveCached writes the binary of program to the cache under key.
+func saveCached(key string, program uint32) error {
+	if key == "" {
+		return nil
+	}
+	var length int32
+	gl.GetProgramiv(program, gl.PROGRAM_BINARY_LENGTH, &length)
+	if length == 0 {
+		return nil
+	}
+
+	data := make([]byte, 4+length)
+	var format uint32
+	gl.GetProgramBinary(program, length, &length, &format, gl.Ptr(data[4:]))
+	binary.LittleEndian.PutUint32(data, format)
+
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		return err
+	}
+	// Write to a temporary file first, so another run never sees half a
+	// binary.
+	f, err := os.CreateTemp(cacheDir, key+".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data[:4+length]); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), cacheFile(key))
+}
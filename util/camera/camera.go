@@ -0,0 +1,234 @@
+// Package camera moves a view around a scene with the mouse and keyboard,
+// and produces the view and projection matrices for it.
+//
+// A Camera has three modes.  Orbit circles a target like a turntable: drag
+// with the left button to rotate, the right button to pan, and scroll to
+// zoom.  Fly moves
+// like a first person game: drag to look, W A S D to move, Q and E to go
+// down and up, and hold shift to go faster.  Pan2D looks straight at the XY
+// plane: drag to pan and scroll to zoom about the cursor.
+package camera
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+
+	"github.com/go-gl/glfw/v3.1/glfw"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Mode of a Camera.
+type Mode int
+
+// Camera modes.
+const (
+	// Orbit is a turntable rather than an arcball.  Dragging changes Yaw and
+	// Pitch, so up stays on +Y and the view never rolls however it is
+	// dragged, and the state stays two angles which Fly shares when
+	// switching modes and Save writes out readably.
+	Orbit Mode = iota
+	Fly
+	Pan2D
+)
+
+// String returns the name of the mode.
+func (m Mode) String() string {
+	switch m {
+	case Orbit:
+		return "orbit"
+	case Fly:
+		return "fly"
+	default:
+		return "pan2d"
+	}
+}
+
+// maxPitch keeps the camera from looking straight up or down, where the
+// view would flip over.
+const maxPitch = math.Pi/2 - 0.01
+
+// minZoom and maxZoom limit how far a Pan2D camera zooms, before float32
+// runs out of precision.
+const (
+	minZoom = 1e-3
+	maxZoom = 1e3
+)
+
+// Camera is a view of the scene.  The exported fields are its state, which
+// Save and Load write to and read from a file.
+type Camera struct {
+	Mode Mode `json:"mode"`
+
+	// Target an Orbit camera looks at, from Distance away.
+	Target   mgl32.Vec3 `json:"target"`
+	Distance float32    `json:"distance"`
+	// Position of a Fly camera.
+	Position mgl32.Vec3 `json:"position"`
+	// Yaw about the Y axis and Pitch up from the XZ plane in radians.  At 0
+	// both, Orbit cameras are on the +Z side of the target and Fly cameras
+	// look down -Z.
+	Yaw   float32 `json:"yaw"`
+	Pitch float32 `json:"pitch"`
+
+	// Center of the view of a Pan2D camera, and Zoom, which scales it.  At
+	// Zoom 1 the screen is 2 units high.
+	Center mgl32.Vec2 `json:"center"`
+	Zoom   float32    `json:"zoom"`
+
+	// FovY is the vertical field of view in radians of the 3D modes, and
+	// Near and Far the distance to the clipping planes.
+	FovY float32 `json:"fovY"`
+	Near float32 `json:"near"`
+	Far  float32 `json:"far"`
+
+	// RotateSpeed is the radians turned per pixel dragged.
+	RotateSpeed float32 `json:"rotateSpeed"`
+	// ZoomSpeed is the factor zoomed by per notch scrolled.
+	ZoomSpeed float32 `json:"zoomSpeed"`
+	// MoveSpeed is the units per second a Fly camera moves.
+	MoveSpeed float32 `json:"moveSpeed"`
+
+	// aspect is the width of the framebuffer over its height.
+	aspect float32
+	window *glfw.Window
+	// dragging is the button held down, or -1.
+	dragging     glfw.MouseButton
+	lastX, lastY float64
+}
+
+// New returns a camera in the given mode at the origin, with a 90 degree
+// field of view.  An Orbit camera is 5 units from its target.
+func New(mode Mode) *Camera {
+	return &Camera{
+		Mode:        mode,
+		Distance:    5,
+		Zoom:        1,
+		FovY:        math.Pi / 2,
+		Near:        1,
+		Far:         500,
+		RotateSpeed: 0.01,
+		ZoomSpeed:   1.1,
+		MoveSpeed:   5,
+		aspect:      1,
+		dragging:    -1,
+	}
+}
+
+// SetMode switches mode, keeping the eye where it is.
+func (c *Camera) SetMode(mode Mode) {
+	switch {
+	case c.Mode == Orbit && mode == Fly:
+		c.Position = c.Eye()
+	case c.Mode == Fly && mode == Orbit:
+		c.Target = c.Position.Add(c.forward().Mul(c.Distance))
+	}
+	c.Mode = mode
+}
+
+// Resize sets the aspect ratio of the projection from the framebuffer size.
+// Call it from App.Resize.
+func (c *Camera) Resize(width, height int) {
+	if height > 0 {
+		c.aspect = float32(width) / float32(height)
+	}
+}
+
+// forward returns the direction the camera looks in.
+func (c *Camera) forward() mgl32.Vec3 {
+	cp := float32(math.Cos(float64(c.Pitch)))
+	return mgl32.Vec3{
+		-float32(math.Sin(float64(c.Yaw))) * cp,
+		float32(math.Sin(float64(c.Pitch))),
+		-float32(math.Cos(float64(c.Yaw))) * cp,
+	}
+}
+
+// right returns the direction to the right of the camera, in the XZ plane.
+func (c *Camera) right() mgl32.Vec3 {
+	return mgl32.Vec3{float32(math.Cos(float64(c.Yaw))), 0, -float32(math.Sin(float64(c.Yaw)))}
+}
+
+// Eye returns the position of the camera.
+func (c *Camera) Eye() mgl32.Vec3 {
+	switch c.Mode {
+	case Orbit:
+		return c.Target.Sub(c.forward().Mul(c.Distance))
+	case Fly:
+		return c.Position
+	default:
+		return mgl32.Vec3{c.Center.X(), c.Center.Y(), 1}
+	}
+}
+
+// View returns the matrix transforming world space to eye space.
+func (c *Camera) View() mgl32.Mat4 {
+	switch c.Mode {
+	case Orbit:
+		return mgl32.LookAtV(c.Eye(), c.Target, mgl32.Vec3{0, 1, 0})
+	case Fly:
+		return mgl32.LookAtV(c.Position, c.Position.Add(c.forward()), mgl32.Vec3{0, 1, 0})
+	default:
+		return mgl32.Scale3D(c.Zoom, c.Zoom, 1).Mul4(mgl32.Translate3D(-c.Center.X(), -c.Center.Y(), 0))
+	}
+}
+
+// Projection returns the matrix transforming eye space to clip space, a
+// perspective projection for the 3D modes and orthographic for Pan2D.
+func (c *Camera) Projection() mgl32.Mat4 {
+	if c.Mode == Pan2D {
+		return mgl32.Ortho(-c.aspect, c.aspect, -1, 1, -1, 1)
+	}
+	return mgl32.Perspective(c.FovY, c.aspect, c.Near, c.Far)
+}
+
+// Update moves a Fly camera by the keys held down over the last dt
+// seconds.  Call it from App.Update.
+func (c *Camera) Update(dt float64) {
+	if c.Mode != Fly || c.window == nil {
+		return
+	}
+	var move mgl32.Vec3
+	for key, dir := range map[glfw.Key]mgl32.Vec3{
+		glfw.KeyW: c.forward(),
+		glfw.KeyS: c.forward().Mul(-1),
+		glfw.KeyD: c.right(),
+		glfw.KeyA: c.right().Mul(-1),
+		glfw.KeyE: {0, 1, 0},
+		glfw.KeyQ: {0, -1, 0},
+	} {
+		if c.window.GetKey(key) == glfw.Press {
+			move = move.Add(dir)
+		}
+	}
+	if move.Len() == 0 {
+		return
+	}
+	speed := c.MoveSpeed
+	if c.window.GetKey(glfw.KeyLeftShift) == glfw.Press || c.window.GetKey(glfw.KeyRightShift) == glfw.Press {
+		speed *= 4
+	}
+	c.Position = c.Position.Add(move.Normalize().Mul(speed * float32(dt)))
+}
+
+// Save writes the camera's state to filename as JSON.
+func (c *Camera) Save(filename string) error {
+	b, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, append(b, '\n'), 0644)
+}
+
+// Load restores the camera's state from a file written by Save.
+func (c *Camera) Load(filename string) error {
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(b, c); err != nil {
+		return fmt.Errorf("invalid camera file %s: %s", filename, err)
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+package camera
+
+import (
+	"math"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// point transforms p by the view matrix m.
+func point(m mgl32.Mat4, p mgl32.Vec3) mgl32.Vec3 {
+	return m.Mul4x1(p.Vec4(1)).Vec3()
+}
+
+// near reports whether every component of a is within 1e-4 of b.
+func near(a, b mgl32.Vec3) bool {
+	for i := range a {
+		if math.Abs(float64(a[i]-b[i])) > 1e-4 {
+			return false
+		}
+	}
+	return true
+}
+
+func TestEyeView(t *testing.T) {
+	orbit := func(yaw, pitch float32) *Camera {
+		c := New(Orbit)
+		c.Target = mgl32.Vec3{1, 0, 0}
+		c.Yaw, c.Pitch = yaw, pitch
+		return c
+	}
+	fly := New(Fly)
+	fly.Position = mgl32.Vec3{1, 2, 3}
+	fly.Yaw = math.Pi / 2
+	pan := New(Pan2D)
+	pan.Center = mgl32.Vec2{2, 3}
+	pan.Zoom = 2
+
+	s := float32(5 / math.Sqrt2)
+	tests := []struct {
+		name string
+		c    *Camera
+		eye  mgl32.Vec3
+		// view maps points in world space to where View puts them.
+		view map[mgl32.Vec3]mgl32.Vec3
+	}{
+		{
+			name: "orbit from +Z",
+			c:    orbit(0, 0),
+			eye:  mgl32.Vec3{1, 0, 5},
+			view: map[mgl32.Vec3]mgl32.Vec3{
+				{1, 0, 0}: {0, 0, -5},
+				{2, 0, 5}: {1, 0, 0},
+			},
+		},
+		{
+			name: "orbit yawed to +X",
+			c:    orbit(math.Pi/2, 0),
+			eye:  mgl32.Vec3{6, 0, 0},
+			view: map[mgl32.Vec3]mgl32.Vec3{
+				{1, 0, 0}: {0, 0, -5},
+				{6, 1, 0}: {0, 1, 0},
+			},
+		},
+		{
+			name: "orbit pitched up",
+			c:    orbit(0, math.Pi/4),
+			eye:  mgl32.Vec3{1, -s, s},
+			view: map[mgl32.Vec3]mgl32.Vec3{
+				{1, 0, 0}: {0, 0, -5},
+			},
+		},
+		{
+			name: "fly",
+			c:    fly,
+			eye:  mgl32.Vec3{1, 2, 3},
+			view: map[mgl32.Vec3]mgl32.Vec3{
+				{1, 2, 3}: {0, 0, 0},
+				{0, 2, 3}: {0, 0, -1},
+				{1, 2, 2}: {1, 0, 0},
+			},
+		},
+		{
+			name: "pan",
+			c:    pan,
+			eye:  mgl32.Vec3{2, 3, 1},
+			view: map[mgl32.Vec3]mgl32.Vec3{
+				{2, 3, 0}: {0, 0, 0},
+				{3, 3, 0}: {2, 0, 0},
+				{2, 2, 0}: {0, -2, 0},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if eye := tt.c.Eye(); !near(eye, tt.eye) {
+				t.Errorf("Eye() = %v, want %v", eye, tt.eye)
+			}
+			view := tt.c.View()
+			for p, want := range tt.view {
+				if got := point(view, p); !near(got, want) {
+					t.Errorf("View() maps %v to %v, want %v", p, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestTurn(t *testing.T) {
+	tests := []struct {
+		name           string
+		pitch          float32
+		dx, dy         float32
+		yaw, wantPitch float32
+	}{
+		{name: "drag right turns left", dx: 10, yaw: -0.1},
+		{name: "drag up looks up", dy: -10, wantPitch: 0.1},
+		{name: "clamped looking up", pitch: 1.5, dy: -100, wantPitch: maxPitch},
+		{name: "clamped looking down", pitch: -1.5, dy: 100, wantPitch: -maxPitch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New(Orbit)
+			c.Pitch = tt.pitch
+			c.turn(tt.dx, tt.dy)
+			if math.Abs(float64(c.Yaw-tt.yaw)) > 1e-6 || math.Abs(float64(c.Pitch-tt.wantPitch)) > 1e-6 {
+				t.Errorf("yaw, pitch = %g, %g, want %g, %g", c.Yaw, c.Pitch, tt.yaw, tt.wantPitch)
+			}
+		})
+	}
+}
+
+func TestScroll(t *testing.T) {
+	tests := []struct {
+		name string
+		mode Mode
+		dy   float64
+		// get returns the value scrolling changes.
+		get  func(c *Camera) float32
+		want float32
+	}{
+		{name: "orbit in", mode: Orbit, dy: 1, get: func(c *Camera) float32 { return c.Distance }, want: 5 / 1.1},
+		{name: "orbit in to near", mode: Orbit, dy: 100, get: func(c *Camera) float32 { return c.Distance }, want: 1},
+		{name: "orbit out to far", mode: Orbit, dy: -100, get: func(c *Camera) float32 { return c.Distance }, want: 500},
+		{name: "pan in", mode: Pan2D, dy: 2, get: func(c *Camera) float32 { return c.Zoom }, want: 1.21},
+		{name: "pan in to limit", mode: Pan2D, dy: 1000, get: func(c *Camera) float32 { return c.Zoom }, want: maxZoom},
+		{name: "pan out to limit", mode: Pan2D, dy: -1000, get: func(c *Camera) float32 { return c.Zoom }, want: minZoom},
+		{name: "fly speeds up", mode: Fly, dy: 1, get: func(c *Camera) float32 { return c.MoveSpeed }, want: 5.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New(tt.mode)
+			c.Scroll(0, tt.dy)
+			if got := tt.get(c); math.Abs(float64(got-tt.want)) > 1e-4*float64(tt.want) {
+				t.Errorf("after Scroll(0, %g) = %g, want %g", tt.dy, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "test.camera")
+	c := New(Fly)
+	c.Position = mgl32.Vec3{1, 2, 3}
+	c.Yaw, c.Pitch = 0.5, -0.25
+	c.Target = mgl32.Vec3{4, 5, 6}
+	c.Center = mgl32.Vec2{7, 8}
+	c.Zoom = 3
+	if err := c.Save(filename); err != nil {
+		t.Fatal(err)
+	}
+
+	got := New(Orbit)
+	if err := got.Load(filename); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, c) {
+		t.Errorf("loaded %+v, want %+v", got, c)
+	}
+}
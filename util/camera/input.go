@@ -0,0 +1,144 @@
+package camera
+
+import (
+	"math"
+
+	"github.com/go-gl/glfw/v3.1/glfw"
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/hurricanerix/gorb/util"
+)
+
+// Attach drives the camera from the mouse and keyboard of w, usually
+// util.CurrentWindow() in App.Init.  Any callbacks already set on w are
+// still called.  Attach does nothing if w is nil, as it is when headless.
+func (c *Camera) Attach(w *glfw.Window) {
+	if w == nil {
+		return
+	}
+	c.window = w
+
+	var prevButton glfw.MouseButtonCallback
+	prevButton = w.SetMouseButtonCallback(func(w *glfw.Window, button glfw.MouseButton, action glfw.Action, mods glfw.ModifierKey) {
+		if prevButton != nil {
+			prevButton(w, button, action, mods)
+		}
+		c.MouseButton(button, action, mods)
+	})
+	var prevCursor glfw.CursorPosCallback
+	prevCursor = w.SetCursorPosCallback(func(w *glfw.Window, x, y float64) {
+		if prevCursor != nil {
+			prevCursor(w, x, y)
+		}
+		c.CursorPos(x, y)
+	})
+	var prevScroll glfw.ScrollCallback
+	prevScroll = w.SetScrollCallback(func(w *glfw.Window, dx, dy float64) {
+		if prevScroll != nil {
+			prevScroll(w, dx, dy)
+		}
+		c.Scroll(dx, dy)
+	})
+}
+
+// MouseButton starts or stops a drag.
+func (c *Camera) MouseButton(button glfw.MouseButton, action glfw.Action, mods glfw.ModifierKey) {
+	switch {
+	case action == glfw.Press && c.dragging < 0:
+		c.dragging = button
+		if c.window != nil {
+			c.lastX, c.lastY = c.window.GetCursorPos()
+		}
+	case action == glfw.Release && button == c.dragging:
+		c.dragging = -1
+	}
+}
+
+// CursorPos moves the camera by the distance dragged since the last call.
+func (c *Camera) CursorPos(x, y float64) {
+	dx, dy := float32(x-c.lastX), float32(y-c.lastY)
+	c.lastX, c.lastY = x, y
+	if c.dragging < 0 {
+		return
+	}
+
+	switch c.Mode {
+	case Orbit:
+		if c.dragging == glfw.MouseButtonLeft {
+			c.turn(dx, dy)
+			return
+		}
+		// Pan so the target moves with the cursor.
+		scale := 2 * c.Distance * float32(math.Tan(float64(c.FovY/2))) / c.windowHeight()
+		up := c.right().Cross(c.forward())
+		c.Target = c.Target.Sub(c.right().Mul(dx * scale)).Add(up.Mul(dy * scale))
+	case Fly:
+		c.turn(dx, dy)
+	case Pan2D:
+		scale := 2 / c.windowHeight() / c.Zoom
+		c.Center = c.Center.Add(mgl32.Vec2{-dx * scale, dy * scale})
+	}
+}
+
+// turn the camera by a drag of dx, dy pixels.
+func (c *Camera) turn(dx, dy float32) {
+	c.Yaw -= dx * c.RotateSpeed
+	c.Pitch = mgl32.Clamp(c.Pitch-dy*c.RotateSpeed, -maxPitch, maxPitch)
+}
+
+// Scroll zooms in or out by dy notches.  Pan2D zooms about the point under
+// the cursor, and Fly changes its speed instead.  An Orbit camera stays
+// between Near and Far from its target, so the target is never clipped.
+func (c *Camera) Scroll(dx, dy float64) {
+	factor := float32(math.Pow(float64(c.ZoomSpeed), dy))
+	switch c.Mode {
+	case Orbit:
+		c.Distance = mgl32.Clamp(c.Distance/factor, c.Near, c.Far)
+	case Fly:
+		c.MoveSpeed *= factor
+	case Pan2D:
+		// Keep the point under the cursor where it is.
+		before := c.cursorWorld()
+		c.Zoom = mgl32.Clamp(c.Zoom*factor, minZoom, maxZoom)
+		c.Center = c.Center.Add(before.Sub(c.cursorWorld()))
+	}
+}
+
+// cursorWorld returns the point of a Pan2D view under the cursor.
+func (c *Camera) cursorWorld() mgl32.Vec2 {
+	if c.window == nil {
+		return c.Center
+	}
+	w, h := c.window.GetSize()
+	if h == 0 {
+		return c.Center
+	}
+	x, y := c.window.GetCursorPos()
+	scale := 2 / float32(h) / c.Zoom
+	return c.Center.Add(mgl32.Vec2{float32(x-float64(w)/2) * scale, -float32(y-float64(h)/2) * scale})
+}
+
+// windowHeight returns the height of the window in screen coordinates, the
+// units cursor positions are in.
+func (c *Camera) windowHeight() float32 {
+	if c.window != nil {
+		if _, h := c.window.GetSize(); h > 0 {
+			return float32(h)
+		}
+	}
+	return 512
+}
+
+// BindKeys binds F5 to save the camera to filename and F9 to restore it, so
+// a view can be kept between runs.
+func (c *Camera) BindKeys(filename string) {
+	util.BindKey("camera-save", glfw.KeyF5, "Save the camera to "+filename, func() {
+		if err := c.Save(filename); err != nil {
+			util.Logger().Println("Failed to save camera:", err)
+		}
+	})
+	util.BindKey("camera-load", glfw.KeyF9, "Restore the camera from "+filename, func() {
+		if err := c.Load(filename); err != nil {
+			util.Logger().Println("Failed to restore camera:", err)
+		}
+	})
+}
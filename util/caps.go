@@ -0,0 +1,185 @@
+package util
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// Capabilities of a GL context, for comparing machines and drivers.
+type Capabilities struct {
+	Vendor      string    `json:"vendor"`
+	Renderer    string    `json:"renderer"`
+	Version     string    `json:"version"`
+	GLSLVersion string    `json:"glslVersion"`
+	Context     GLVersion `json:"context"`
+	Profile     string    `json:"profile"`
+	Debug       bool      `json:"debug"`
+	Extensions  []string  `json:"extensions"`
+	// Limits by GL enum name, such as GL_MAX_VERTEX_ATTRIBS.  Limits the
+	// context does not support are left out.
+	Limits map[string]int64 `json:"limits"`
+}
+
+// limit is an implementation limit to query.
+type limit struct {
+	name  string
+	pname uint32
+	// count of values for indexed limits, 0 if it is a single value.
+	count int
+}
+
+// Compute shader limits, which the 4.1 bindings do not define.
+const (
+	maxComputeUniformBlocks         = 0x91BB
+	maxComputeWorkGroupInvocations  = 0x90EB
+	maxComputeWorkGroupCount        = 0x91BE
+	maxComputeWorkGroupSize         = 0x91BF
+	maxComputeSharedMemorySize      = 0x8262
+	maxShaderStorageBlockSize       = 0x90DE
+	maxShaderStorageBufferBindings  = 0x90DD
+	maxCombinedShaderStorageBlocks  = 0x90DC
+	maxCombinedShaderOutputResource = 0x8F39
+)
+
+// limits reported for every context, in the order they are printed.
+var limits = []limit{
+	{"GL_MAX_VERTEX_ATTRIBS", gl.MAX_VERTEX_ATTRIBS, 0},
+	{"GL_MAX_VERTEX_OUTPUT_COMPONENTS", gl.MAX_VERTEX_OUTPUT_COMPONENTS, 0},
+	{"GL_MAX_VARYING_COMPONENTS", gl.MAX_VARYING_COMPONENTS, 0},
+	{"GL_MAX_TEXTURE_IMAGE_UNITS", gl.MAX_TEXTURE_IMAGE_UNITS, 0},
+	{"GL_MAX_COMBINED_TEXTURE_IMAGE_UNITS", gl.MAX_COMBINED_TEXTURE_IMAGE_UNITS, 0},
+	{"GL_MAX_TEXTURE_SIZE", gl.MAX_TEXTURE_SIZE, 0},
+	{"GL_MAX_3D_TEXTURE_SIZE", gl.MAX_3D_TEXTURE_SIZE, 0},
+	{"GL_MAX_ARRAY_TEXTURE_LAYERS", gl.MAX_ARRAY_TEXTURE_LAYERS, 0},
+	{"GL_MAX_RENDERBUFFER_SIZE", gl.MAX_RENDERBUFFER_SIZE, 0},
+	{"GL_MAX_SAMPLES", gl.MAX_SAMPLES, 0},
+	{"GL_MAX_COLOR_ATTACHMENTS", gl.MAX_COLOR_ATTACHMENTS, 0},
+	{"GL_MAX_DRAW_BUFFERS", gl.MAX_DRAW_BUFFERS, 0},
+	{"GL_MAX_UNIFORM_BLOCK_SIZE", gl.MAX_UNIFORM_BLOCK_SIZE, 0},
+	{"GL_MAX_UNIFORM_BUFFER_BINDINGS", gl.MAX_UNIFORM_BUFFER_BINDINGS, 0},
+	{"GL_MAX_VERTEX_UNIFORM_BLOCKS", gl.MAX_VERTEX_UNIFORM_BLOCKS, 0},
+	{"GL_MAX_FRAGMENT_UNIFORM_BLOCKS", gl.MAX_FRAGMENT_UNIFORM_BLOCKS, 0},
+	{"GL_MAX_COMBINED_UNIFORM_BLOCKS", gl.MAX_COMBINED_UNIFORM_BLOCKS, 0},
+	{"GL_MAX_VERTEX_UNIFORM_COMPONENTS", gl.MAX_VERTEX_UNIFORM_COMPONENTS, 0},
+	{"GL_MAX_FRAGMENT_UNIFORM_COMPONENTS", gl.MAX_FRAGMENT_UNIFORM_COMPONENTS, 0},
+	{"GL_MAX_GEOMETRY_OUTPUT_VERTICES", gl.MAX_GEOMETRY_OUTPUT_VERTICES, 0},
+	{"GL_MAX_PATCH_VERTICES", gl.MAX_PATCH_VERTICES, 0},
+	{"GL_MAX_TESS_GEN_LEVEL", gl.MAX_TESS_GEN_LEVEL, 0},
+	{"GL_MAX_TESS_CONTROL_UNIFORM_COMPONENTS", gl.MAX_TESS_CONTROL_UNIFORM_COMPONENTS, 0},
+	{"GL_MAX_TESS_EVALUATION_UNIFORM_COMPONENTS", gl.MAX_TESS_EVALUATION_UNIFORM_COMPONENTS, 0},
+}
+
+// computeLimits are only reported by contexts with compute shaders.
+var computeLimits = []limit{
+	{"GL_MAX_COMPUTE_WORK_GROUP_INVOCATIONS", maxComputeWorkGroupInvocations, 0},
+	{"GL_MAX_COMPUTE_WORK_GROUP_COUNT", maxComputeWorkGroupCount, 3},
+	{"GL_MAX_COMPUTE_WORK_GROUP_SIZE", maxComputeWorkGroupSize, 3},
+	{"GL_MAX_COMPUTE_SHARED_MEMORY_SIZE", maxComputeSharedMemorySize, 0},
+	{"GL_MAX_COMPUTE_UNIFORM_BLOCKS", maxComputeUniformBlocks, 0},
+	{"GL_MAX_SHADER_STORAGE_BLOCK_SIZE", maxShaderStorageBlockSize, 0},
+	{"GL_MAX_SHADER_STORAGE_BUFFER_BINDINGS", maxShaderStorageBufferBindings, 0},
+	{"GL_MAX_COMBINED_SHADER_STORAGE_BLOCKS", maxCombinedShaderStorageBlocks, 0},
+	{"GL_MAX_COMBINED_SHADER_OUTPUT_RESOURCES", maxCombinedShaderOutputResource, 0},
+}
+
+// QueryCapabilities of the current context.
+func QueryCapabilities() Capabilities {
+	c := Capabilities{
+		Vendor:      gl.GoStr(gl.GetString(gl.VENDOR)),
+		Renderer:    gl.GoStr(gl.GetString(gl.RENDERER)),
+		Version:     gl.GoStr(gl.GetString(gl.VERSION)),
+		GLSLVersion: gl.GoStr(gl.GetString(gl.SHADING_LANGUAGE_VERSION)),
+		Limits:      map[string]int64{},
+	}
+
+	var major, minor, mask, flags int32
+	gl.GetIntegerv(gl.MAJOR_VERSION, &major)
+	gl.GetIntegerv(gl.MINOR_VERSION, &minor)
+	gl.GetIntegerv(gl.CONTEXT_PROFILE_MASK, &mask)
+	gl.GetIntegerv(gl.CONTEXT_FLAGS, &flags)
+	c.Context = GLVersion{int(major), int(minor)}
+	c.Profile = CoreProfile.String()
+	if mask&gl.CONTEXT_COMPATIBILITY_PROFILE_BIT != 0 {
+		c.Profile = CompatProfile.String()
+	}
+	c.Debug = flags&gl.CONTEXT_FLAG_DEBUG_BIT != 0
+
+	var n int32
+	gl.GetIntegerv(gl.NUM_EXTENSIONS, &n)
+	for i := uint32(0); i < uint32(n); i++ {
+		c.Extensions = append(c.Extensions, gl.GoStr(gl.GetStringi(gl.EXTENSIONS, i)))
+	}
+	sort.Strings(c.Extensions)
+
+	query := limits
+	if !c.Context.Less(GLVersion{4, 3}) || c.HasExtension("GL_ARB_compute_shader") {
+		query = append(query[:len(query):len(query)], computeLimits...)
+	}
+	// Clear any earlier errors, so an error after a query means the driver
+	// does not know the limit.
+	for i := 0; i < 16 && gl.GetError() != gl.NO_ERROR; i++ {
+	}
+	for _, l := range query {
+		if l.count == 0 {
+			var v int64
+			gl.GetInteger64v(l.pname, &v)
+			if gl.GetError() == gl.NO_ERROR {
+				c.Limits[l.name] = v
+			}
+			continue
+		}
+		for i := 0; i < l.count; i++ {
+			var v int32
+			gl.GetIntegeri_v(l.pname, uint32(i), &v)
+			if gl.GetError() == gl.NO_ERROR {
+				c.Limits[fmt.Sprintf("%s[%d]", l.name, i)] = int64(v)
+			}
+		}
+	}
+
+	return c
+}
+
+// HasExtension reports whether the context supports the named extension.
+func (c Capabilities) HasExtension(name string) bool {
+	i := sort.SearchStrings(c.Extensions, name)
+	return i < len(c.Extensions) && c.Extensions[i] == name
+}
+
+// String formats the capabilities as a human readable report.
+func (c Capabilities) String() string {
+	buf := new(bytes.Buffer)
+	tw := tabwriter.NewWriter(buf, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "Vendor\t%s\n", c.Vendor)
+	fmt.Fprintf(tw, "Renderer\t%s\n", c.Renderer)
+	fmt.Fprintf(tw, "Version\t%s\n", c.Version)
+	fmt.Fprintf(tw, "GLSL version\t%s\n", c.GLSLVersion)
+	fmt.Fprintf(tw, "Context\t%s %s", c.Context, c.Profile)
+	if c.Debug {
+		fmt.Fprintf(tw, ", debug")
+	}
+	fmt.Fprintf(tw, "\n\nLimits:\n")
+
+	for _, l := range append(limits[:len(limits):len(limits)], computeLimits...) {
+		if v, ok := c.Limits[l.name]; ok {
+			fmt.Fprintf(tw, "  %s\t%d\n", l.name, v)
+		}
+		for i := 0; i < l.count; i++ {
+			name := fmt.Sprintf("%s[%d]", l.name, i)
+			if v, ok := c.Limits[name]; ok {
+				fmt.Fprintf(tw, "  %s\t%d\n", name, v)
+			}
+		}
+	}
+	tw.Flush()
+
+	fmt.Fprintf(buf, "\nExtensions (%d):\n", len(c.Extensions))
+	for _, e := range c.Extensions {
+		fmt.Fprintf(buf, "  %s\n", e)
+	}
+	return buf.String()
+}
@@ -0,0 +1,151 @@
+package util
+
+import (
+	"math"
+	"time"
+)
+
+// TimeSource returns the time in seconds since some fixed point.
+type TimeSource func() float64
+
+// RealTime is a TimeSource reading the monotonic clock.
+func RealTime() TimeSource {
+	start := time.Now()
+	return func() float64 {
+		return time.Since(start).Seconds()
+	}
+}
+
+// FixedTime is a TimeSource which advances by step seconds every time it
+// is read, so every run sees the same frame times.  Captures and tests use
+// it to render reproducible frames.
+func FixedTime(step float64) TimeSource {
+	t := -step
+	return func() float64 {
+		t += step
+		return t
+	}
+}
+
+// Clock measures the time between frames, and turns it into the fixed size
+// steps a simulation needs to behave the same at any frame rate.  Time can
+// be paused, stepped a frame at a time, and sped up or slowed down.
+type Clock struct {
+	// Source of the time, RealTime if nil.
+	Source TimeSource
+	// Scale multiplies the time passing, 1 is real time.
+	Scale float64
+	// FixedStep is the length in seconds of the steps returned by Steps.
+	FixedStep float64
+	// MaxSteps limits the steps run in a single frame, so a slow frame does
+	// not cause ever more steps to be needed to catch up.
+	MaxSteps int
+
+	// last time read from Source.
+	last    float64
+	started bool
+	// dt and elapsed scaled time in seconds.
+	dt, elapsed float64
+	frame       int
+	paused      bool
+	// step is set when a single step has been requested while paused.
+	step bool
+	// accumulator of time not yet consumed by Steps.
+	accumulator float64
+}
+
+// NewClock returns a clock reading source, running at normal speed with
+// fixed steps of 1/60 of a second.
+func NewClock(source TimeSource) *Clock {
+	return &Clock{
+		Source:    source,
+		Scale:     1,
+		FixedStep: 1.0 / 60.0,
+		MaxSteps:  8,
+	}
+}
+
+// Tick advances the clock by the time since the last Tick, and returns the
+// scaled delta time.  Run calls this once per frame.
+func (c *Clock) Tick() float64 {
+	if c.Source == nil {
+		c.Source = RealTime()
+	}
+	if !c.started {
+		c.last = c.Source()
+		c.started = true
+	}
+	now := c.Source()
+	elapsed := now - c.last
+	c.last = now
+
+	switch {
+	case c.step:
+		c.dt = c.FixedStep
+		c.step = false
+	case c.paused:
+		c.dt = 0
+	default:
+		c.dt = elapsed * c.Scale
+	}
+	c.elapsed += c.dt
+	c.accumulator += c.dt
+	c.frame++
+	return c.dt
+}
+
+// Dt returns the scaled time in seconds between the last two ticks, 0 while
+// paused.
+func (c *Clock) Dt() float64 {
+	return c.dt
+}
+
+// Elapsed returns the scaled time in seconds since the clock started, not
+// counting time spent paused.
+func (c *Clock) Elapsed() float64 {
+	return c.elapsed
+}
+
+// Frame returns the number of ticks so far.
+func (c *Clock) Frame() int {
+	return c.frame
+}
+
+// Steps returns how many fixed steps of FixedStep seconds are due, and
+// consumes them.  Whatever is left over carries into the next frame.
+func (c *Clock) Steps() int {
+	if c.FixedStep <= 0 {
+		return 0
+	}
+	n := int(math.Floor(c.accumulator / c.FixedStep))
+	c.accumulator -= float64(n) * c.FixedStep
+	if c.MaxSteps > 0 && n > c.MaxSteps {
+		n = c.MaxSteps
+		c.accumulator = 0
+	}
+	return n
+}
+
+// Alpha returns how far into the next fixed step the clock is, from 0 to 1,
+// for interpolating between the last two steps when rendering.
+func (c *Clock) Alpha() float64 {
+	if c.FixedStep <= 0 {
+		return 0
+	}
+	return c.accumulator / c.FixedStep
+}
+
+// Pause or resume the clock.
+func (c *Clock) Pause(paused bool) {
+	c.paused = paused
+}
+
+// Paused reports whether the clock is paused.
+func (c *Clock) Paused() bool {
+	return c.paused
+}
+
+// Step advances a paused clock by one FixedStep on the next Tick.
+func (c *Clock) Step() {
+	c.step = true
+}
@@ -0,0 +1,137 @@
+package util
+
+import "testing"
+
+// The times below are multiples of 1/8, so the sums are exact.
+
+func TestFixedTime(t *testing.T) {
+	source := FixedTime(0.25)
+	for i, want := range []float64{0, 0.25, 0.5, 0.75} {
+		if got := source(); got != want {
+			t.Errorf("read %d = %g, want %g", i, got, want)
+		}
+	}
+}
+
+func TestClockScale(t *testing.T) {
+	c := NewClock(FixedTime(0.25))
+	if dt := c.Tick(); dt != 0.25 {
+		t.Errorf("dt = %g, want 0.25", dt)
+	}
+	c.Scale = 2
+	if dt := c.Tick(); dt != 0.5 {
+		t.Errorf("dt at scale 2 = %g, want 0.5", dt)
+	}
+	c.Scale = 0.5
+	if dt := c.Tick(); dt != 0.125 {
+		t.Errorf("dt at scale 0.5 = %g, want 0.125", dt)
+	}
+	if c.Elapsed() != 0.875 || c.Frame() != 3 {
+		t.Errorf("elapsed %g after %d frames, want 0.875 after 3", c.Elapsed(), c.Frame())
+	}
+}
+
+func TestClockPauseAndStep(t *testing.T) {
+	c := NewClock(FixedTime(0.25))
+	c.FixedStep = 0.125
+	c.Tick()
+
+	c.Pause(true)
+	if !c.Paused() {
+		t.Fatal("not paused")
+	}
+	for i := 0; i < 3; i++ {
+		if dt := c.Tick(); dt != 0 {
+			t.Errorf("dt while paused = %g, want 0", dt)
+		}
+	}
+	if c.Elapsed() != 0.25 || c.Frame() != 4 {
+		t.Errorf("elapsed %g after %d frames, want 0.25 after 4", c.Elapsed(), c.Frame())
+	}
+
+	c.Step()
+	if dt := c.Tick(); dt != 0.125 {
+		t.Errorf("dt of a step = %g, want the fixed step 0.125", dt)
+	}
+	if dt := c.Tick(); dt != 0 {
+		t.Errorf("dt after a step = %g, want 0", dt)
+	}
+
+	// Time passed while paused is not made up on resuming.
+	c.Pause(false)
+	if dt := c.Tick(); dt != 0.25 {
+		t.Errorf("dt after resuming = %g, want 0.25", dt)
+	}
+	if c.Elapsed() != 0.625 {
+		t.Errorf("elapsed = %g, want 0.625", c.Elapsed())
+	}
+}
+
+func TestClockSteps(t *testing.T) {
+	c := NewClock(FixedTime(0.375))
+	c.FixedStep = 0.25
+
+	tests := []struct {
+		steps int
+		alpha float64
+	}{
+		{1, 0.5}, // 0.375 accumulated
+		{2, 0},   // 0.125 + 0.375
+		{1, 0.5}, // 0.375
+		{2, 0},   // 0.125 + 0.375
+	}
+	for i, tt := range tests {
+		c.Tick()
+		if n := c.Steps(); n != tt.steps {
+			t.Errorf("frame %d: steps = %d, want %d", i, n, tt.steps)
+		}
+		if a := c.Alpha(); a != tt.alpha {
+			t.Errorf("frame %d: alpha = %g, want %g", i, a, tt.alpha)
+		}
+		if n := c.Steps(); n != 0 {
+			t.Errorf("frame %d: steps were not consumed, %d more", i, n)
+		}
+	}
+}
+
+func TestClockMaxSteps(t *testing.T) {
+	c := NewClock(FixedTime(10))
+	c.FixedStep = 0.25
+	c.MaxSteps = 8
+
+	c.Tick()
+	if n := c.Steps(); n != 8 {
+		t.Errorf("steps = %d, want MaxSteps", n)
+	}
+	// The rest of a slow frame is dropped rather than caught up on.
+	if a := c.Alpha(); a != 0 {
+		t.Errorf("alpha = %g, want 0", a)
+	}
+}
+
+func TestClockPausedSteps(t *testing.T) {
+	c := NewClock(FixedTime(0.25))
+	c.FixedStep = 0.25
+	c.Tick()
+	c.Steps()
+
+	c.Pause(true)
+	c.Tick()
+	if n := c.Steps(); n != 0 {
+		t.Errorf("steps while paused = %d, want 0", n)
+	}
+	c.Step()
+	c.Tick()
+	if n := c.Steps(); n != 1 {
+		t.Errorf("steps after Step = %d, want 1", n)
+	}
+}
+
+func TestClockNoFixedStep(t *testing.T) {
+	c := NewClock(FixedTime(0.25))
+	c.FixedStep = 0
+	c.Tick()
+	if n, a := c.Steps(), c.Alpha(); n != 0 || a != 0 {
+		t.Errorf("steps %d, alpha %g without a fixed step, want 0, 0", n, a)
+	}
+}
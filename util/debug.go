@@ -0,0 +1,275 @@
+package util
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"runtime"
+	"strings"
+	"unsafe"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// DebugSeverity of a GL debug message, in increasing order.
+type DebugSeverity int
+
+// Severities of debug messages, matching GL_DEBUG_SEVERITY_*.
+const (
+	DebugNotification DebugSeverity = iota
+	DebugLow
+	DebugMedium
+	DebugHigh
+)
+
+// String returns the severity as a word.
+func (s DebugSeverity) String() string {
+	switch s {
+	case DebugNotification:
+		return "notification"
+	case DebugLow:
+		return "low"
+	case DebugMedium:
+		return "medium"
+	default:
+		return "high"
+	}
+}
+
+// DebugOptions control how GL debug messages are reported.
+type DebugOptions struct {
+	// Logger messages are written to, standard error if nil.
+	Logger *log.Logger
+	// MinSeverity of the messages to log, lower ones are dropped.
+	MinSeverity DebugSeverity
+	// Panic on high severity messages.  The driver reports messages from
+	// inside the GL call, where panicking is unsafe, so the panic comes once
+	// the call has returned: from Run after the App callback which made it,
+	// or from CheckError.  The message carries the Go stack of the call
+	// which caused it.
+	Panic bool
+}
+
+// debug is the state of debug output for the current context.
+var debug struct {
+	// context requests a debug context from NewWindow and NewHeadless.
+	context bool
+	// enabled once EnableDebugOutput has been called.
+	enabled bool
+	// polling glGetError, as neither KHR_debug nor ARB_debug_output is
+	// available.
+	polling bool
+	opts    DebugOptions
+	// seen counts the times each message has been received, so repeats
+	// are only logged occasionally.
+	seen map[string]int
+	// pending is the first high severity message to panic with, and the
+	// stack it was received on.
+	pending string
+}
+
+// SetDebugContext makes NewWindow and NewHeadless request a debug context,
+// which is needed for most drivers to send debug messages.  Run calls this
+// when Options.Debug is set.
+func SetDebugContext(enable bool) {
+	debug.context = enable
+}
+
+// EnableDebugOutput routes GL debug messages for the current context to
+// opts.Logger.  KHR_debug is used if available, then ARB_debug_output.
+// Without either, glGetError is polled by Run after each call into the App,
+// and by CheckError.
+func EnableDebugOutput(opts DebugOptions) {
+	if opts.Logger == nil {
+		opts.Logger = log.New(os.Stderr, "gl: ", 0)
+	}
+	debug.enabled = true
+	debug.opts = opts
+	debug.seen = map[string]int{}
+	debug.polling = false
+	debug.pending = ""
+
+	switch {
+	case hasExtension("GL_KHR_debug"):
+		gl.Enable(gl.DEBUG_OUTPUT)
+		gl.Enable(gl.DEBUG_OUTPUT_SYNCHRONOUS)
+		gl.DebugMessageCallback(debugCallback, nil)
+		gl.DebugMessageControl(gl.DONT_CARE, gl.DONT_CARE, gl.DONT_CARE, 0, nil, true)
+	case hasExtension("GL_ARB_debug_output"):
+		gl.Enable(gl.DEBUG_OUTPUT_SYNCHRONOUS_ARB)
+		gl.DebugMessageCallbackARB(debugCallback, nil)
+		gl.DebugMessageControlARB(gl.DONT_CARE, gl.DONT_CARE, gl.DONT_CARE, 0, nil, true)
+	default:
+		debug.polling = true
+		opts.Logger.Println("debug output is not supported, polling glGetError instead")
+	}
+}
+
+// hasExtension reports whether the current context supports the named
+// extension.
+func hasExtension(name string) bool {
+	var n int32
+	gl.GetIntegerv(gl.NUM_EXTENSIONS, &n)
+	for i := uint32(0); i < uint32(n); i++ {
+		if gl.GoStr(gl.GetStringi(gl.EXTENSIONS, i)) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// debugCallback receives messages from the driver.  Debug output is
+// synchronous, so it runs on the goroutine which made the offending call.
+func debugCallback(source, xtype, id, severity uint32, length int32, message string, userParam unsafe.Pointer) {
+	logDebug(debugSeverity(severity), fmt.Sprintf("%s %s %d: %s",
+		debugSource(source), debugType(xtype), id, strings.TrimSpace(message)))
+}
+
+// logDebug logs msg if it is severe enough, dropping repeats other than
+// every power of ten.  High severity messages are kept for panicPending if
+// asked to panic, as this can be called from inside the driver.
+func logDebug(severity DebugSeverity, msg string) {
+	if severity < debug.opts.MinSeverity {
+		return
+	}
+	debug.seen[msg]++
+	switch n := debug.seen[msg]; {
+	case n == 1:
+		debug.opts.Logger.Printf("%s: %s", severity, msg)
+	case isPowerOf10(n):
+		debug.opts.Logger.Printf("%s: %s (repeated %d times)", severity, msg, n)
+	}
+	if severity == DebugHigh && debug.opts.Panic && debug.pending == "" {
+		buf := make([]byte, 64<<10)
+		debug.pending = msg + "\n\n" + string(buf[:runtime.Stack(buf, false)])
+	}
+}
+
+// panicPending panics with the message kept by logDebug, if any.  It is
+// only called from Go, never from inside a GL call.
+func panicPending() {
+	if msg := debug.pending; msg != "" {
+		debug.pending = ""
+		panic("gl: " + msg)
+	}
+}
+
+// isPowerOf10 reports whether n is 10, 100, 1000...
+func isPowerOf10(n int) bool {
+	for n >= 10 && n%10 == 0 {
+		n /= 10
+	}
+	return n == 1
+}
+
+// CheckError polls glGetError, logging any errors as having happened in op
+// when debug output has been enabled.  It is useful for narrowing down
+// where an error comes from when only polling is available.
+func CheckError(op string) {
+	if !debug.enabled {
+		return
+	}
+	readErrors(op)
+	panicPending()
+}
+
+// readErrors logs the errors glGetError reports as having happened in op.
+func readErrors(op string) {
+	// Limit the errors read, glGetError can report an error forever once
+	// the context is lost.
+	for i := 0; i < 16; i++ {
+		code := gl.GetError()
+		if code == gl.NO_ERROR {
+			return
+		}
+		logDebug(DebugHigh, fmt.Sprintf("%s in %s", errorName(code), op))
+	}
+}
+
+// pollErrors is CheckError for Run, called after each App method.  It only
+// reads glGetError if there is no debug callback to report errors as they
+// happen, but panics with any message the callback kept.
+func pollErrors(op string) {
+	if debug.polling {
+		readErrors(op)
+	}
+	panicPending()
+}
+
+// pollCallbackErrors is pollErrors for App methods called from GLFW event
+// callbacks, which are inside glfw.PollEvents.  Run panics once PollEvents
+// has returned instead.
+func pollCallbackErrors(op string) {
+	if debug.polling {
+		readErrors(op)
+	}
+}
+
+// debugSeverity converts GL_DEBUG_SEVERITY_* to a DebugSeverity.  The ARB
+// constants have the same values.
+func debugSeverity(severity uint32) DebugSeverity {
+	switch severity {
+	case gl.DEBUG_SEVERITY_HIGH:
+		return DebugHigh
+	case gl.DEBUG_SEVERITY_MEDIUM:
+		return DebugMedium
+	case gl.DEBUG_SEVERITY_LOW:
+		return DebugLow
+	default:
+		return DebugNotification
+	}
+}
+
+// debugSource names GL_DEBUG_SOURCE_*.
+func debugSource(source uint32) string {
+	switch source {
+	case gl.DEBUG_SOURCE_API:
+		return "api"
+	case gl.DEBUG_SOURCE_WINDOW_SYSTEM:
+		return "window system"
+	case gl.DEBUG_SOURCE_SHADER_COMPILER:
+		return "shader compiler"
+	case gl.DEBUG_SOURCE_THIRD_PARTY:
+		return "third party"
+	case gl.DEBUG_SOURCE_APPLICATION:
+		return "application"
+	default:
+		return "other"
+	}
+}
+
+// debugType names GL_DEBUG_TYPE_*.
+func debugType(xtype uint32) string {
+	switch xtype {
+	case gl.DEBUG_TYPE_ERROR:
+		return "error"
+	case gl.DEBUG_TYPE_DEPRECATED_BEHAVIOR:
+		return "deprecated"
+	case gl.DEBUG_TYPE_UNDEFINED_BEHAVIOR:
+		return "undefined behavior"
+	case gl.DEBUG_TYPE_PORTABILITY:
+		return "portability"
+	case gl.DEBUG_TYPE_PERFORMANCE:
+		return "performance"
+	default:
+		return "other"
+	}
+}
+
+// errorName names a glGetError code.
+func errorName(code uint32) string {
+	switch code {
+	case gl.INVALID_ENUM:
+		return "GL_INVALID_ENUM"
+	case gl.INVALID_VALUE:
+		return "GL_INVALID_VALUE"
+	case gl.INVALID_OPERATION:
+		return "GL_INVALID_OPERATION"
+	case gl.INVALID_FRAMEBUFFER_OPERATION:
+		return "GL_INVALID_FRAMEBUFFER_OPERATION"
+	case gl.OUT_OF_MEMORY:
+		return "GL_OUT_OF_MEMORY"
+	default:
+		return fmt.Sprintf("GL error 0x%x", code)
+	}
+}
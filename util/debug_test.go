@@ -0,0 +1,44 @@
+package util
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDebugPanicIsDeferred(t *testing.T) {
+	saved := debug
+	defer func() { debug = saved }()
+	debug.opts = DebugOptions{Logger: Logger(), MinSeverity: DebugHigh, Panic: true}
+	debug.seen = map[string]int{}
+	debug.pending = ""
+
+	// As called from the debug callback, which must not panic.
+	logDebug(DebugHigh, "api error 1282: first")
+	logDebug(DebugHigh, "api error 1282: second")
+
+	defer func() {
+		msg, _ := recover().(string)
+		if !strings.HasPrefix(msg, "gl: api error 1282: first\n") {
+			t.Errorf("panic = %q, want the first message", msg)
+		}
+		if !strings.Contains(msg, "TestDebugPanicIsDeferred") {
+			t.Errorf("panic does not include the stack the message arrived on:\n%s", msg)
+		}
+		if debug.pending != "" {
+			t.Errorf("pending = %q after panicking", debug.pending)
+		}
+	}()
+	panicPending()
+	t.Error("panicPending did not panic")
+}
+
+func TestDebugNoPanic(t *testing.T) {
+	saved := debug
+	defer func() { debug = saved }()
+	debug.opts = DebugOptions{Logger: Logger(), MinSeverity: DebugHigh}
+	debug.seen = map[string]int{}
+	debug.pending = ""
+
+	logDebug(DebugHigh, "api error 1282")
+	panicPending()
+}
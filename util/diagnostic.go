@@ -0,0 +1,187 @@
+package util
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Severity of a Diagnostic.
+type Severity int
+
+// Severities reported by the compiler and linker.
+const (
+	SeverityError Severity = iota
+	SeverityWarning
+	SeverityInfo
+)
+
+// String returns the severity as it appears in compiler logs.
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	default:
+		return "info"
+	}
+}
+
+// Diagnostic is a single message from a shader compile or program link log.
+type Diagnostic struct {
+	// File the message refers to, empty if the log did not say.
+	File string
+	// Line and Column the message refers to, 0 if the log did not say.
+	Line, Column int
+	Severity     Severity
+	Message      string
+
+	// source line the message refers to, used to point at the problem.
+	source string
+}
+
+// String formats the diagnostic as file:line:column: severity: message,
+// followed by the offending source line with a caret under the column.
+func (d Diagnostic) String() string {
+	buf := new(bytes.Buffer)
+	if d.File != "" {
+		buf.WriteString(d.File)
+		if d.Line > 0 {
+			fmt.Fprintf(buf, ":%d", d.Line)
+			if d.Column > 0 {
+				fmt.Fprintf(buf, ":%d", d.Column)
+			}
+		}
+		buf.WriteString(": ")
+	}
+	fmt.Fprintf(buf, "%s: %s", d.Severity, d.Message)
+
+	if d.source != "" {
+		fmt.Fprintf(buf, "\n\t%s", d.source)
+		if d.Column > 0 && d.Column <= len(d.source)+1 {
+			// Keep tabs so the caret lines up with the source above it.
+			indent := strings.Map(func(r rune) rune {
+				if r == '\t' {
+					return r
+				}
+				return ' '
+			}, d.source[:d.Column-1])
+			fmt.Fprintf(buf, "\n\t%s^", indent)
+		}
+	}
+	return buf.String()
+}
+
+// ShaderError is returned when a shader fails to compile or a program fails
+// to link.
+type ShaderError struct {
+	// Filename of the shader which failed to compile, empty if the program
+	// failed to link.
+	Filename string
+	// Diagnostics parsed from the info log.
+	Diagnostics []Diagnostic
+	// Log is the info log as returned by GL.
+	Log string
+}
+
+// Error lists every diagnostic, pointing at the offending source lines.
+func (e *ShaderError) Error() string {
+	buf := new(bytes.Buffer)
+	if e.Filename != "" {
+		fmt.Fprintf(buf, "failed to compile %s:", e.Filename)
+	} else {
+		buf.WriteString("failed to link program:")
+	}
+	for _, d := range e.Diagnostics {
+		fmt.Fprintf(buf, "\n%s", d)
+	}
+	return buf.String()
+}
+
+// Patterns for the lines of an info log, in the formats used by each vendor.
+// The leading number is the source string number, which the preprocessor
+// maps to a file.
+var (
+	// Mesa: 0:12(5): error: `x' undeclared
+	mesaLog = regexp.MustCompile(`^(\d+):(\d+)\((\d+)\): (\w+): (.*)$`)
+	// NVIDIA: 0(12) : error C1008: undefined variable "x"
+	nvidiaLog = regexp.MustCompile(`^(\d+)\((\d+)\) : (\w+(?: error)?) (.*)$`)
+	// AMD, Intel and Apple: ERROR: 0:12: 'x' : undeclared identifier
+	amdLog = regexp.MustCompile(`^(ERROR|WARNING|INFO): (\d+):(\d+): (.*)$`)
+	// Anything without a location, typically from the linker.
+	plainLog = regexp.MustCompile(`^(?i)(error|warning|info)?:?\s*(.*)$`)
+	// AMD's summary line, which repeats what the other lines said.
+	amdSummary = regexp.MustCompile(`^ERROR: \d+ compilation errors?\.`)
+)
+
+// parseLog splits an info log into diagnostics, mapping source string
+// numbers back to the files in sources.
+func parseLog(log string, sources []sourceFile) []Diagnostic {
+	var diags []Diagnostic
+	for _, line := range strings.Split(log, "\n") {
+		line = strings.TrimRight(line, "\r\x00 ")
+		if line == "" || amdSummary.MatchString(line) {
+			continue
+		}
+
+		var d Diagnostic
+		var source string
+		if m := mesaLog.FindStringSubmatch(line); m != nil {
+			source = m[1]
+			d.Line, _ = strconv.Atoi(m[2])
+			d.Column, _ = strconv.Atoi(m[3])
+			d.Severity = parseSeverity(m[4])
+			d.Message = m[5]
+		} else if m := nvidiaLog.FindStringSubmatch(line); m != nil {
+			source = m[1]
+			d.Line, _ = strconv.Atoi(m[2])
+			d.Severity = parseSeverity(m[3])
+			d.Message = m[4]
+		} else if m := amdLog.FindStringSubmatch(line); m != nil {
+			source = m[2]
+			d.Line, _ = strconv.Atoi(m[3])
+			d.Severity = parseSeverity(m[1])
+			d.Message = m[4]
+		} else if m := plainLog.FindStringSubmatch(line); m != nil && m[1] != "" {
+			d.Severity = parseSeverity(m[1])
+			d.Message = m[2]
+		} else if len(diags) > 0 {
+			// A continuation of the previous message.
+			diags[len(diags)-1].Message += "\n" + line
+			continue
+		} else {
+			d.Message = line
+		}
+
+		if n, err := strconv.Atoi(source); err == nil && n < len(sources) {
+			d.File = sources[n].name
+			d.source = sourceLine(sources[n].text, d.Line)
+		}
+		diags = append(diags, d)
+	}
+	return diags
+}
+
+// parseSeverity converts the severity used in a log to a Severity.
+func parseSeverity(s string) Severity {
+	switch s = strings.ToLower(s); {
+	case strings.Contains(s, "error"):
+		return SeverityError
+	case strings.Contains(s, "warning"):
+		return SeverityWarning
+	default:
+		return SeverityInfo
+	}
+}
+
+// sourceLine returns line n of text, counting from 1.
+func sourceLine(text []byte, n int) string {
+	lines := strings.Split(string(text), "\n")
+	if n < 1 || n > len(lines) {
+		return ""
+	}
+	return strings.TrimRight(lines[n-1], "\r")
+}
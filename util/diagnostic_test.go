@@ -0,0 +1,150 @@
+package util
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseLog(t *testing.T) {
+	sources := []sourceFile{
+		{name: "main.frag", text: []byte("#version 410\nout vec4 color;\nvoid main() {\n\tcolor = x;\n}\n")},
+		{name: "common.glsl", text: []byte("float f() {\n  return 1\n}\n")},
+	}
+
+	tests := []struct {
+		name string
+		log  string
+		want []Diagnostic
+	}{
+		{
+			name: "mesa",
+			log: "0:4(10): error: `x' undeclared\n" +
+				"1:3(1): error: syntax error, unexpected '}', expecting ',' or ';'\n",
+			want: []Diagnostic{
+				{File: "main.frag", Line: 4, Column: 10, Severity: SeverityError, Message: "`x' undeclared", source: "\tcolor = x;"},
+				{File: "common.glsl", Line: 3, Column: 1, Severity: SeverityError, Message: "syntax error, unexpected '}', expecting ',' or ';'", source: "}"},
+			},
+		},
+		{
+			name: "mesa warning",
+			log:  "0:4(2): warning: `color' used uninitialized\n",
+			want: []Diagnostic{
+				{File: "main.frag", Line: 4, Column: 2, Severity: SeverityWarning, Message: "`color' used uninitialized", source: "\tcolor = x;"},
+			},
+		},
+		{
+			name: "nvidia",
+			log: "0(4) : error C1008: undefined variable \"x\"\n" +
+				"1(2) : warning C7050: \"f\" might be used before being initialized\n",
+			want: []Diagnostic{
+				{File: "main.frag", Line: 4, Severity: SeverityError, Message: "C1008: undefined variable \"x\"", source: "\tcolor = x;"},
+				{File: "common.glsl", Line: 2, Severity: SeverityWarning, Message: "C7050: \"f\" might be used before being initialized", source: "  return 1"},
+			},
+		},
+		{
+			name: "nvidia fatal error",
+			log:  "0(5) : fatal error C9999: unexpected end of file\n",
+			want: []Diagnostic{
+				{File: "main.frag", Line: 5, Severity: SeverityError, Message: "C9999: unexpected end of file", source: "}"},
+			},
+		},
+		{
+			name: "amd",
+			log: "ERROR: 0:4: 'x' : undeclared identifier \n" +
+				"WARNING: 1:2: 'return' : missing semicolon\n" +
+				"ERROR: 1 compilation errors.  No code generated.\n\n\x00",
+			want: []Diagnostic{
+				{File: "main.frag", Line: 4, Severity: SeverityError, Message: "'x' : undeclared identifier", source: "\tcolor = x;"},
+				{File: "common.glsl", Line: 2, Severity: SeverityWarning, Message: "'return' : missing semicolon", source: "  return 1"},
+			},
+		},
+		{
+			name: "linker",
+			log: "error: linking with uncompiled/unspecialized shader\n" +
+				"warning: fragment shader output `color' is never written\n",
+			want: []Diagnostic{
+				{Severity: SeverityError, Message: "linking with uncompiled/unspecialized shader"},
+				{Severity: SeverityWarning, Message: "fragment shader output `color' is never written"},
+			},
+		},
+		{
+			name: "continuation",
+			log: "ERROR: 0:4: 'x' : undeclared identifier\n" +
+				"  did you mean 'f'?\r\n",
+			want: []Diagnostic{
+				{File: "main.frag", Line: 4, Severity: SeverityError, Message: "'x' : undeclared identifier\n  did you mean 'f'?", source: "\tcolor = x;"},
+			},
+		},
+		{
+			name: "unknown source string",
+			log:  "7:1(1): error: something\n",
+			want: []Diagnostic{
+				{Line: 1, Column: 1, Severity: SeverityError, Message: "something"},
+			},
+		},
+		{
+			name: "unrecognised",
+			log:  "Vertex shader failed to compile\n",
+			want: []Diagnostic{
+				{Severity: SeverityError, Message: "Vertex shader failed to compile"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseLog(tt.log, sources)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseLog() =\n%#v\nwant\n%#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiagnosticString(t *testing.T) {
+	tests := []struct {
+		name string
+		d    Diagnostic
+		want string
+	}{
+		{
+			name: "caret under column",
+			d:    Diagnostic{File: "main.frag", Line: 4, Column: 10, Severity: SeverityError, Message: "`x' undeclared", source: "\tcolor = x;"},
+			want: "main.frag:4:10: error: `x' undeclared\n" +
+				"\t\tcolor = x;\n" +
+				"\t\t        ^",
+		},
+		{
+			name: "caret after end of line",
+			d:    Diagnostic{File: "a.glsl", Line: 2, Column: 11, Severity: SeverityError, Message: "expected ';'", source: "  return 1"},
+			want: "a.glsl:2:11: error: expected ';'\n" +
+				"\t  return 1\n" +
+				"\t          ^",
+		},
+		{
+			name: "no column",
+			d:    Diagnostic{File: "main.frag", Line: 4, Severity: SeverityWarning, Message: "C7050", source: "\tcolor = x;"},
+			want: "main.frag:4: warning: C7050\n" +
+				"\t\tcolor = x;",
+		},
+		{
+			name: "column past the line",
+			d:    Diagnostic{File: "a.glsl", Line: 1, Column: 40, Severity: SeverityError, Message: "bad", source: "}"},
+			want: "a.glsl:1:40: error: bad\n" +
+				"\t}",
+		},
+		{
+			name: "no location",
+			d:    Diagnostic{Severity: SeverityInfo, Message: "linked"},
+			want: "info: linked",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.d.String(); got != tt.want {
+				t.Errorf("String() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
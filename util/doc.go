@@ -1,2 +1,5 @@
 // Package util manages helpers like window creation and compiling shaders.
+//
+// Each example implements App and hands it to Run, which owns the window,
+// the main loop and the cleanup of any GL objects created through util.
 package util
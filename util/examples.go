@@ -0,0 +1,61 @@
+package util
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Example describes an example for the gorb command to list and run.  Each
+// example package registers itself from init, so importing it is all the
+// command needs to know about it.
+type Example struct {
+	// Chapter of the book the example comes from.
+	Chapter int
+	// Name of the example, unique within the chapter.
+	Name string
+	// Description shown by gorb list.
+	Description string
+	// New returns the App to run.
+	New func() App
+	// Options to run it with, which the command line can override.
+	// Options.Requires says what the example needs of the context.
+	Options Options
+}
+
+// ID names the example on the command line, such as ch03/primitive-restart.
+func (e Example) ID() string {
+	return fmt.Sprintf("ch%02d/%s", e.Chapter, e.Name)
+}
+
+// examples registered so far, by ID.
+var examples = map[string]Example{}
+
+// Register an example.  It panics if the ID is already taken, as that can
+// only be a mistake in the example.
+func Register(e Example) {
+	if _, ok := examples[e.ID()]; ok {
+		panic("util: example " + e.ID() + " registered twice")
+	}
+	examples[e.ID()] = e
+}
+
+// Examples returns every registered example in chapter order.
+func Examples() []Example {
+	var es []Example
+	for _, e := range examples {
+		es = append(es, e)
+	}
+	sort.Slice(es, func(i, j int) bool {
+		if es[i].Chapter != es[j].Chapter {
+			return es[i].Chapter < es[j].Chapter
+		}
+		return es[i].Name < es[j].Name
+	})
+	return es
+}
+
+// LookupExample returns the example with the given ID.
+func LookupExample(id string) (Example, bool) {
+	e, ok := examples[id]
+	return e, ok
+}
@@ -0,0 +1,60 @@
+package util
+
+import (
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/glfw/v3.1/glfw"
+)
+
+// framebuffer is the size of the default framebuffer, kept up to date by
+// Run.
+var framebuffer struct {
+	width, height int
+	// scale of framebuffer pixels to window coordinates, 2 on a Retina
+	// display.
+	scale float32
+}
+
+// FramebufferSize returns the size in pixels of the framebuffer being
+// rendered to.  On HiDPI displays this is larger than the window size.
+func FramebufferSize() (width, height int) {
+	return framebuffer.width, framebuffer.height
+}
+
+// Aspect returns the width of the framebuffer divided by its height.
+func Aspect() float32 {
+	if framebuffer.height == 0 {
+		return 1
+	}
+	return float32(framebuffer.width) / float32(framebuffer.height)
+}
+
+// ContentScale returns the ratio of framebuffer pixels to window
+// coordinates, 1 on a normal display and 2 on a Retina display.  Anything
+// sized in pixels, such as line widths or point sizes, should be multiplied
+// by it.
+func ContentScale() float32 {
+	if framebuffer.scale == 0 {
+		return 1
+	}
+	return framebuffer.scale
+}
+
+// setFramebufferSize records the new size and sets the viewport to cover
+// it.  It reports whether the size or scale changed.
+func setFramebufferSize(width, height int, scale float32) bool {
+	changed := width != framebuffer.width || height != framebuffer.height || scale != framebuffer.scale
+	framebuffer.width, framebuffer.height, framebuffer.scale = width, height, scale
+	gl.Viewport(0, 0, int32(width), int32(height))
+	return changed
+}
+
+// windowScale returns the content scale of w, from the ratio of its
+// framebuffer to its window size as GLFW 3.1 has no content scale query.
+func windowScale(w *glfw.Window) float32 {
+	fw, _ := w.GetFramebufferSize()
+	ww, _ := w.GetSize()
+	if ww == 0 {
+		return 1
+	}
+	return float32(fw) / float32(ww)
+}
@@ -0,0 +1,120 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// Window is the part of *glfw.Window used by Run.  Headless implements it so
+// it can stand in for a real window.
+type Window interface {
+	ShouldClose() bool
+	SetShouldClose(value bool)
+	SwapBuffers()
+	GetFramebufferSize() (width, height int)
+	Destroy()
+}
+
+// Headless renders into a framebuffer object on an offscreen GL context
+// instead of a window, so examples can run without a display.
+type Headless struct {
+	ctx           *offscreenContext
+	width, height int
+	shouldClose   bool
+
+	// Framebuffer everything is rendered into.
+	fbo           uint32
+	renderbuffers [2]uint32
+}
+
+// NewHeadless offscreen context is returned, with a framebuffer of
+// opts.Width by opts.Height.  The version, profile and SRGB options are used
+// as they are by NewWindow, the rest only apply to windows.
+func NewHeadless(opts WindowOptions) (*Headless, error) {
+	var ctx *offscreenContext
+	var version GLVersion
+	var errs []string
+	for _, v := range opts.versions() {
+		var err error
+		if ctx, err = newOffscreenContext(v, opts.Profile, debug.context); err == nil {
+			version = v
+			break
+		}
+		errs = append(errs, err.Error())
+	}
+	if ctx == nil {
+		return nil, fmt.Errorf("failed to create headless context: %s", strings.Join(errs, "; "))
+	}
+	if want := opts.versions()[0]; version != want {
+		logger.Printf("GL %s is not available, using %s\n", want, version)
+	}
+
+	if err := gl.InitWithProcAddrFunc(ctx.getProcAddress); err != nil {
+		ctx.destroy()
+		return nil, fmt.Errorf("unable to initialize Glow ... exiting: %s", err)
+	}
+
+	width, height := opts.Width, opts.Height
+	h := &Headless{ctx: ctx, width: width, height: height}
+	format := uint32(gl.RGBA8)
+	if opts.SRGB {
+		format = gl.SRGB8_ALPHA8
+		gl.Enable(gl.FRAMEBUFFER_SRGB)
+	}
+
+	// A surfaceless context has no default framebuffer, so bind one of our
+	// own in its place.  The examples never bind another.
+	gl.GenFramebuffers(1, &h.fbo)
+	gl.BindFramebuffer(gl.FRAMEBUFFER, h.fbo)
+	gl.GenRenderbuffers(2, &h.renderbuffers[0])
+	gl.BindRenderbuffer(gl.RENDERBUFFER, h.renderbuffers[0])
+	gl.RenderbufferStorage(gl.RENDERBUFFER, format, int32(width), int32(height))
+	gl.FramebufferRenderbuffer(gl.FRAMEBUFFER, gl.COLOR_ATTACHMENT0, gl.RENDERBUFFER, h.renderbuffers[0])
+	gl.BindRenderbuffer(gl.RENDERBUFFER, h.renderbuffers[1])
+	gl.RenderbufferStorage(gl.RENDERBUFFER, gl.DEPTH24_STENCIL8, int32(width), int32(height))
+	gl.FramebufferRenderbuffer(gl.FRAMEBUFFER, gl.DEPTH_STENCIL_ATTACHMENT, gl.RENDERBUFFER, h.renderbuffers[1])
+	gl.BindRenderbuffer(gl.RENDERBUFFER, 0)
+
+	if status := gl.CheckFramebufferStatus(gl.FRAMEBUFFER); status != gl.FRAMEBUFFER_COMPLETE {
+		h.Destroy()
+		return nil, fmt.Errorf("offscreen framebuffer incomplete: 0x%x", status)
+	}
+	gl.Viewport(0, 0, int32(width), int32(height))
+
+	return h, nil
+}
+
+// ShouldClose reports whether SetShouldClose(true) has been called.
+func (h *Headless) ShouldClose() bool {
+	return h.shouldClose
+}
+
+// SetShouldClose sets the value returned by ShouldClose.
+func (h *Headless) SetShouldClose(value bool) {
+	h.shouldClose = value
+}
+
+// SwapBuffers waits for rendering to finish, there is nothing to swap.
+func (h *Headless) SwapBuffers() {
+	gl.Finish()
+}
+
+// GetFramebufferSize returns the size of the offscreen framebuffer.
+func (h *Headless) GetFramebufferSize() (width, height int) {
+	return h.width, h.height
+}
+
+// Framebuffer returns the ID of the framebuffer object being rendered into.
+func (h *Headless) Framebuffer() uint32 {
+	return h.fbo
+}
+
+// Destroy the framebuffer and the context.
+func (h *Headless) Destroy() {
+	gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+	gl.DeleteRenderbuffers(2, &h.renderbuffers[0])
+	gl.DeleteFramebuffers(1, &h.fbo)
+	h.ctx.destroy()
+}
@@ -0,0 +1,123 @@
+//go:build linux && cgo
+
+package util
+
+/*
+#cgo LDFLAGS: -lEGL
+#include <stdlib.h>
+#include <EGL/egl.h>
+#include <EGL/eglext.h>
+
+#ifndef EGL_PLATFORM_SURFACELESS_MESA
+#define EGL_PLATFORM_SURFACELESS_MESA 0x31DD
+#endif
+#ifndef EGL_CONTEXT_OPENGL_DEBUG
+#define EGL_CONTEXT_OPENGL_DEBUG 0x31B0
+#endif
+
+// surfacelessDisplay returns the Mesa surfaceless platform display if it is
+// available, otherwise the default display.
+static EGLDisplay surfacelessDisplay() {
+	PFNEGLGETPLATFORMDISPLAYEXTPROC getPlatformDisplay =
+		(PFNEGLGETPLATFORMDISPLAYEXTPROC)eglGetProcAddress("eglGetPlatformDisplayEXT");
+	if (getPlatformDisplay != NULL) {
+		EGLDisplay dpy = getPlatformDisplay(EGL_PLATFORM_SURFACELESS_MESA, EGL_DEFAULT_DISPLAY, NULL);
+		if (dpy != EGL_NO_DISPLAY) {
+			return dpy;
+		}
+	}
+	return eglGetDisplay(EGL_DEFAULT_DISPLAY);
+}
+
+// createContext creates a context of the given version and profile,
+// optionally a debug context.
+static EGLContext createContext(EGLDisplay dpy, EGLint major, EGLint minor, EGLint profile, EGLBoolean debug) {
+	const EGLint configAttribs[] = {
+		EGL_SURFACE_TYPE, EGL_PBUFFER_BIT,
+		EGL_RENDERABLE_TYPE, EGL_OPENGL_BIT,
+		EGL_NONE
+	};
+	EGLConfig config;
+	EGLint n = 0;
+	if (!eglChooseConfig(dpy, configAttribs, &config, 1, &n) || n < 1) {
+		return EGL_NO_CONTEXT;
+	}
+
+	const EGLint contextAttribs[] = {
+		EGL_CONTEXT_MAJOR_VERSION, major,
+		EGL_CONTEXT_MINOR_VERSION, minor,
+		EGL_CONTEXT_OPENGL_PROFILE_MASK, profile,
+		EGL_CONTEXT_OPENGL_DEBUG, debug,
+		EGL_NONE
+	};
+	return eglCreateContext(dpy, config, EGL_NO_CONTEXT, contextAttribs);
+}
+*/
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// offscreenContext is a surfaceless EGL context.  Mesa provides this on any
+// driver, including llvmpipe when there is no GPU.
+type offscreenContext struct {
+	display C.EGLDisplay
+	context C.EGLContext
+}
+
+// newOffscreenContext creates a GL context of the given version and profile
+// and makes it current.
+func newOffscreenContext(version GLVersion, profile Profile, debug bool) (*offscreenContext, error) {
+	dpy := C.surfacelessDisplay()
+	if dpy == 0 {
+		return nil, fmt.Errorf("no EGL display")
+	}
+	if C.eglInitialize(dpy, nil, nil) == C.EGL_FALSE {
+		return nil, fmt.Errorf("eglInitialize: 0x%x", C.eglGetError())
+	}
+	if C.eglBindAPI(C.EGL_OPENGL_API) == C.EGL_FALSE {
+		err := fmt.Errorf("eglBindAPI: 0x%x", C.eglGetError())
+		C.eglTerminate(dpy)
+		return nil, err
+	}
+
+	var debugBit C.EGLBoolean = C.EGL_FALSE
+	if debug {
+		debugBit = C.EGL_TRUE
+	}
+	var profileBit C.EGLint = C.EGL_CONTEXT_OPENGL_CORE_PROFILE_BIT
+	if profile == CompatProfile {
+		profileBit = C.EGL_CONTEXT_OPENGL_COMPATIBILITY_PROFILE_BIT
+	}
+	ctx := C.createContext(dpy, C.EGLint(version.Major), C.EGLint(version.Minor), profileBit, debugBit)
+	if ctx == nil {
+		err := fmt.Errorf("could not create GL %s %s context: 0x%x", version, profile, C.eglGetError())
+		C.eglTerminate(dpy)
+		return nil, err
+	}
+
+	if C.eglMakeCurrent(dpy, nil, nil, ctx) == C.EGL_FALSE {
+		err := fmt.Errorf("eglMakeCurrent: 0x%x", C.eglGetError())
+		C.eglDestroyContext(dpy, ctx)
+		C.eglTerminate(dpy)
+		return nil, err
+	}
+
+	return &offscreenContext{display: dpy, context: ctx}, nil
+}
+
+// getProcAddress is used to load the GL functions.
+func (c *offscreenContext) getProcAddress(name string) unsafe.Pointer {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	return unsafe.Pointer(C.eglGetProcAddress(cname))
+}
+
+// destroy the context.
+func (c *offscreenContext) destroy() {
+	C.eglMakeCurrent(c.display, nil, nil, nil)
+	C.eglDestroyContext(c.display, c.context)
+	C.eglTerminate(c.display)
+}
@@ -0,0 +1,22 @@
+//go:build !linux || !cgo
+
+package util
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// offscreenContext is only implemented on linux, using EGL.
+type offscreenContext struct{}
+
+// newOffscreenContext always fails on this platform.
+func newOffscreenContext(version GLVersion, profile Profile, debug bool) (*offscreenContext, error) {
+	return nil, fmt.Errorf("headless mode requires EGL, which is only supported on linux")
+}
+
+func (c *offscreenContext) getProcAddress(name string) unsafe.Pointer {
+	return nil
+}
+
+func (c *offscreenContext) destroy() {}
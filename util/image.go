@@ -0,0 +1,41 @@
+package util
+
+import (
+	"image"
+	"image/png"
+	"os"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// readPixels from the bound read framebuffer.  GL stores rows bottom to top,
+// so they are flipped to match image.Image.
+func readPixels(x, y, width, height int) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	gl.PixelStorei(gl.PACK_ALIGNMENT, 1)
+	gl.ReadPixels(int32(x), int32(y), int32(width), int32(height), gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(img.Pix))
+
+	row := make([]uint8, img.Stride)
+	for top, bottom := 0, height-1; top < bottom; top, bottom = top+1, bottom-1 {
+		t := img.Pix[top*img.Stride : (top+1)*img.Stride]
+		b := img.Pix[bottom*img.Stride : (bottom+1)*img.Stride]
+		copy(row, t)
+		copy(t, b)
+		copy(b, row)
+	}
+
+	return img
+}
+
+// writePNG encodes img to filename.
+func writePNG(filename string, img image.Image) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(f, img); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,170 @@
+package util
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/go-gl/glfw/v3.1/glfw"
+)
+
+// Binding of a key to a named action.
+type Binding struct {
+	// Name of the action, used to unbind it.
+	Name string
+	Key  glfw.Key
+	// Description shown in the help.
+	Description string
+
+	fn func()
+}
+
+// bindings in the order they were made.  Every binding for a key is called
+// when it is pressed, so bindings chain rather than replace each other.
+var bindings []*Binding
+
+// BindKey calls fn whenever key is pressed.  Examples bind their controls in
+// Init, and the bindings are listed by pressing H or F1.  A key bound to
+// several actions calls each of them in the order they were bound.  Once a
+// key is bound, App.Key no longer receives its events.
+func BindKey(name string, key glfw.Key, description string, fn func()) {
+	bindings = append(bindings, &Binding{Name: name, Key: key, Description: description, fn: fn})
+}
+
+// UnbindKey removes every binding for the named action.
+func UnbindKey(name string) {
+	kept := bindings[:0]
+	for _, b := range bindings {
+		if b.Name != name {
+			kept = append(kept, b)
+		}
+	}
+	bindings = kept
+}
+
+// Bindings returns the current key bindings.
+func Bindings() []Binding {
+	var bs []Binding
+	for _, b := range bindings {
+		bs = append(bs, *b)
+	}
+	return bs
+}
+
+// handleKey calls the actions bound to key when it is pressed, and reports
+// whether key is bound, so its other events are not passed on either.
+func handleKey(key glfw.Key, action glfw.Action) bool {
+	bound := false
+	// Copy the bindings, so an action can bind or unbind keys.
+	for _, b := range append([]*Binding(nil), bindings...) {
+		if b.Key == key {
+			if action == glfw.Press {
+				b.fn()
+			}
+			bound = true
+		}
+	}
+	return bound
+}
+
+// bindDefaultKeys binds Escape to close w, and H and F1 to print the help.
+func bindDefaultKeys(w *glfw.Window) {
+	BindKey("quit", glfw.KeyEscape, "Close the window", func() {
+		w.SetShouldClose(true)
+	})
+	for _, key := range []glfw.Key{glfw.KeyH, glfw.KeyF1} {
+		BindKey("help", key, "Show this help", func() {
+			logger.Print(KeyHelp())
+		})
+	}
+}
+
+// bindClockKeys binds the keys controlling clock.
+func bindClockKeys(clock *Clock) {
+	BindKey("pause", glfw.KeyP, "Pause or resume time", func() {
+		clock.Pause(!clock.Paused())
+	})
+	BindKey("step", glfw.KeyPeriod, "Advance one step while paused", clock.Step)
+	BindKey("slower", glfw.KeyLeftBracket, "Halve the speed of time", func() {
+		clock.Scale /= 2
+		logger.Printf("Time scale %g\n", clock.Scale)
+	})
+	BindKey("faster", glfw.KeyRightBracket, "Double the speed of time", func() {
+		clock.Scale *= 2
+		logger.Printf("Time scale %g\n", clock.Scale)
+	})
+}
+
+// resetKeys removes every binding.
+func resetKeys() {
+	bindings = nil
+}
+
+// KeyHelp returns the list of bindings, one action per line with the keys
+// bound to it.
+func KeyHelp() string {
+	var names []string
+	keys := map[string][]string{}
+	descriptions := map[string]string{}
+	for _, b := range bindings {
+		if _, ok := keys[b.Name]; !ok {
+			names = append(names, b.Name)
+			descriptions[b.Name] = b.Description
+		}
+		keys[b.Name] = append(keys[b.Name], keyName(b.Key))
+	}
+
+	buf := new(bytes.Buffer)
+	buf.WriteString("Keys:\n")
+	tw := tabwriter.NewWriter(buf, 0, 4, 2, ' ', 0)
+	for _, name := range names {
+		fmt.Fprintf(tw, "  %s\t%s\n", strings.Join(keys[name], ", "), descriptions[name])
+	}
+	tw.Flush()
+	return buf.String()
+}
+
+// keyNames of the keys which are not a letter, digit or function key.
+var keyNames = map[glfw.Key]string{
+	glfw.KeySpace:        "Space",
+	glfw.KeyApostrophe:   "'",
+	glfw.KeyComma:        ",",
+	glfw.KeyMinus:        "-",
+	glfw.KeyPeriod:       ".",
+	glfw.KeySlash:        "/",
+	glfw.KeySemicolon:    ";",
+	glfw.KeyEqual:        "=",
+	glfw.KeyLeftBracket:  "[",
+	glfw.KeyBackslash:    "\\",
+	glfw.KeyRightBracket: "]",
+	glfw.KeyGraveAccent:  "`",
+	glfw.KeyEscape:       "Escape",
+	glfw.KeyEnter:        "Enter",
+	glfw.KeyTab:          "Tab",
+	glfw.KeyBackspace:    "Backspace",
+	glfw.KeyInsert:       "Insert",
+	glfw.KeyDelete:       "Delete",
+	glfw.KeyRight:        "Right",
+	glfw.KeyLeft:         "Left",
+	glfw.KeyDown:         "Down",
+	glfw.KeyUp:           "Up",
+	glfw.KeyPageUp:       "PageUp",
+	glfw.KeyPageDown:     "PageDown",
+	glfw.KeyHome:         "Home",
+	glfw.KeyEnd:          "End",
+}
+
+// keyName returns the name of key as shown in the help.
+func keyName(key glfw.Key) string {
+	switch {
+	case key >= glfw.KeyA && key <= glfw.KeyZ, key >= glfw.Key0 && key <= glfw.Key9:
+		return string(rune(key))
+	case key >= glfw.KeyF1 && key <= glfw.KeyF25:
+		return fmt.Sprintf("F%d", key-glfw.KeyF1+1)
+	}
+	if name, ok := keyNames[key]; ok {
+		return name
+	}
+	return fmt.Sprintf("key %d", key)
+}
@@ -0,0 +1,106 @@
+package util
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-gl/glfw/v3.1/glfw"
+)
+
+func TestBindKey(t *testing.T) {
+	defer resetKeys()
+
+	var calls []string
+	BindKey("first", glfw.KeyM, "First action", func() { calls = append(calls, "first") })
+	BindKey("second", glfw.KeyM, "Second action", func() { calls = append(calls, "second") })
+	BindKey("other", glfw.KeyN, "Other action", func() { calls = append(calls, "other") })
+
+	if !handleKey(glfw.KeyM, glfw.Press) {
+		t.Error("M is not reported as bound")
+	}
+	if want := []string{"first", "second"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("pressing M called %q, want %q", calls, want)
+	}
+
+	// Other events on a bound key are swallowed without calling anything.
+	calls = nil
+	for _, action := range []glfw.Action{glfw.Repeat, glfw.Release} {
+		if !handleKey(glfw.KeyM, action) {
+			t.Errorf("M is not reported as bound for action %d", action)
+		}
+	}
+	if len(calls) != 0 {
+		t.Errorf("repeat and release called %q", calls)
+	}
+
+	if handleKey(glfw.KeyB, glfw.Press) {
+		t.Error("B is reported as bound")
+	}
+
+	UnbindKey("first")
+	calls = nil
+	handleKey(glfw.KeyM, glfw.Press)
+	if want := []string{"second"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("pressing M after unbinding called %q, want %q", calls, want)
+	}
+}
+
+func TestBindKeyFromAction(t *testing.T) {
+	defer resetKeys()
+
+	calls := 0
+	BindKey("bind", glfw.KeyB, "Bind another action", func() {
+		BindKey("bound", glfw.KeyB, "Bound by an action", func() { calls++ })
+	})
+
+	// The new binding takes effect from the next press.
+	handleKey(glfw.KeyB, glfw.Press)
+	if calls != 0 {
+		t.Errorf("binding made during a press was called %d times", calls)
+	}
+	handleKey(glfw.KeyB, glfw.Press)
+	if calls != 1 {
+		t.Errorf("binding was called %d times, want 1", calls)
+	}
+}
+
+func TestKeyHelp(t *testing.T) {
+	defer resetKeys()
+
+	BindKey("quit", glfw.KeyEscape, "Close the window", func() {})
+	BindKey("help", glfw.KeyH, "Show this help", func() {})
+	BindKey("help", glfw.KeyF1, "Show this help", func() {})
+	BindKey("slower", glfw.KeyLeftBracket, "Halve the speed of time", func() {})
+	BindKey("digit", glfw.Key1, "Pick the first", func() {})
+
+	want := "Keys:\n" +
+		"  Escape  Close the window\n" +
+		"  H, F1   Show this help\n" +
+		"  [       Halve the speed of time\n" +
+		"  1       Pick the first\n"
+	if got := KeyHelp(); got != want {
+		t.Errorf("KeyHelp() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestKeyName(t *testing.T) {
+	tests := []struct {
+		key  glfw.Key
+		want string
+	}{
+		{glfw.KeyA, "A"},
+		{glfw.KeyZ, "Z"},
+		{glfw.Key0, "0"},
+		{glfw.KeyF1, "F1"},
+		{glfw.KeyF12, "F12"},
+		{glfw.KeyPeriod, "."},
+		{glfw.KeySpace, "Space"},
+		{glfw.KeyEscape, "Escape"},
+		{glfw.KeyKPEnter, "key 335"},
+	}
+	for _, tt := range tests {
+		if got := keyName(tt.key); got != tt.want {
+			t.Errorf("keyName(%d) = %q, want %q", tt.key, got, tt.want)
+		}
+	}
+}
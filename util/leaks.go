@@ -0,0 +1,134 @@
+package util
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// LeakMode controls what happens to GL objects still alive when the
+// context is torn down.
+type LeakMode int
+
+// Leak modes.  Tracking records the stack each object is created from, so
+// it is off by default.
+const (
+	// LeaksIgnore deletes leaked objects without saying anything.
+	LeaksIgnore LeakMode = iota
+	// LeaksReport prints each leaked object with the stack it was created
+	// from.
+	LeaksReport
+	// LeaksFail reports leaks, and makes Run return an error if there were
+	// any, so a test harness fails.
+	LeaksFail
+)
+
+// leakMode in use, see SetLeakMode.
+var leakMode LeakMode
+
+// leaked objects swept up by Run rather than deleted by the App.
+var leaked []Leak
+
+// SetLeakMode changes how leaked objects are handled.  Only objects created
+// after tracking is enabled have a creation stack.  Run sets this from
+// Options.Leaks.
+func SetLeakMode(mode LeakMode) {
+	leakMode = mode
+}
+
+// Leak is a GL object created through this package which was not deleted.
+type Leak struct {
+	// Kind of object, such as "buffer".
+	Kind string
+	ID   uint32
+	// Stack the object was created from.
+	Stack string
+}
+
+// String formats the leak with its creation stack.
+func (l Leak) String() string {
+	s := fmt.Sprintf("%s %d", l.Kind, l.ID)
+	if l.Stack != "" {
+		s += " created at:\n" + strings.TrimRight(l.Stack, "\n")
+	}
+	return s
+}
+
+// Leaks returns every object which was swept up by Run or is still alive.
+func Leaks() []Leak {
+	leaks := append([]Leak(nil), leaked...)
+	return append(leaks, alive()...)
+}
+
+// alive returns the objects which have not been deleted, in a stable order.
+func alive() []Leak {
+	var leaks []Leak
+	for _, s := range []*objectSet{
+		objects.programs, objects.shaders, objects.pipelines,
+		objects.buffers, objects.vaos, objects.textures, objects.framebuffers,
+	} {
+		var ids []uint32
+		for id := range s.ids {
+			ids = append(ids, id)
+		}
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+		for _, id := range ids {
+			leaks = append(leaks, Leak{Kind: s.kind, ID: id, Stack: s.ids[id]})
+		}
+	}
+	return leaks
+}
+
+// recordLeaks remembers the objects still alive before Run deletes them.
+func recordLeaks() {
+	if leakMode != LeaksIgnore {
+		leaked = append(leaked, alive()...)
+	}
+}
+
+// reportLeaks prints every leaked object.
+func reportLeaks() {
+	if leakMode == LeaksIgnore {
+		return
+	}
+	leaks := Leaks()
+	if len(leaks) == 0 {
+		return
+	}
+	logger.Printf("Leaked %d GL objects:\n", len(leaks))
+	for _, l := range leaks {
+		logger.Println(l)
+	}
+}
+
+// trackingFiles are this file and objects.go, which only do the tracking,
+// so their frames are left out of creation stacks.
+var trackingFiles = func() map[string]bool {
+	_, file, _, _ := runtime.Caller(0)
+	return map[string]bool{
+		file:                                    true,
+		path.Join(path.Dir(file), "objects.go"): true,
+	}
+}()
+
+// callers returns the stack of the function which created an object,
+// skipping the runtime and the frames inside trackingFiles.
+func callers() string {
+	pc := make([]uintptr, 32)
+	n := runtime.Callers(1, pc)
+	frames := runtime.CallersFrames(pc[:n])
+	buf := new(bytes.Buffer)
+	for {
+		f, more := frames.Next()
+		if !strings.HasPrefix(f.Function, "runtime.") && !trackingFiles[f.File] {
+			fmt.Fprintf(buf, "\t%s\n\t\t%s:%d\n", f.Function, f.File, f.Line)
+		}
+		if !more {
+			break
+		}
+	}
+	return buf.String()
+}
@@ -0,0 +1,24 @@
+package util
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCallers(t *testing.T) {
+	defer SetLeakMode(leakMode)
+	SetLeakMode(LeaksReport)
+
+	s := newObjectSet("buffer")
+	s.add(1)
+	stack := s.ids[1]
+	lines := strings.Split(strings.TrimSpace(stack), "\n")
+	if !strings.HasSuffix(lines[0], ".TestCallers") {
+		t.Errorf("stack starts at %s, want TestCallers:\n%s", strings.TrimSpace(lines[0]), stack)
+	}
+	for _, file := range []string{"leaks.go", "objects.go"} {
+		if strings.Contains(stack, "/util/"+file+":") {
+			t.Errorf("stack includes frames from %s:\n%s", file, stack)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package util
+
+import (
+	"log"
+	"os"
+)
+
+// logger receives warnings and status messages from util.  It writes to
+// standard error, leaving standard output to the App and to recordings
+// streamed there.
+var logger = log.New(os.Stderr, "", 0)
+
+// SetLogger changes where warnings and status messages from util go.
+func SetLogger(l *log.Logger) {
+	logger = l
+}
+
+// Logger returns the logger warnings and status messages from util go to,
+// for packages built on util to report through as well.
+func Logger() *log.Logger {
+	return logger
+}
@@ -0,0 +1,233 @@
+package util
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// Mesh owns a VAO along with the vertex buffer and optional index buffer it
+// reads from, and knows enough about them to draw without the caller
+// keeping counts and types alongside.
+type Mesh struct {
+	// VAO holding the attribute setup.
+	VAO uint32
+	// VBO holding the vertices.
+	VBO uint32
+	// EBO holding the indices, 0 if the mesh is not indexed.
+	EBO uint32
+	// Mode of the primitives to draw, such as gl.TRIANGLES.
+	Mode uint32
+	// NumVertices in the VBO.
+	NumVertices int32
+	// NumIndices in the EBO, 0 if the mesh is not indexed.
+	NumIndices int32
+	// IndexType is gl.UNSIGNED_BYTE, gl.UNSIGNED_SHORT or gl.UNSIGNED_INT.
+	IndexType uint32
+}
+
+// indexTypes for each type of index slice, with their size in bytes.
+var indexTypes = map[reflect.Type]struct {
+	glType uint32
+	size   int
+}{
+	reflect.TypeOf([]uint8(nil)):  {gl.UNSIGNED_BYTE, 1},
+	reflect.TypeOf([]uint16(nil)): {gl.UNSIGNED_SHORT, 2},
+	reflect.TypeOf([]uint32(nil)): {gl.UNSIGNED_INT, 4},
+}
+
+// NewMesh creates a mesh drawing mode primitives from vertices, a slice of
+// a struct tagged as described for VertexLayout, which are interleaved in a
+// single buffer.  Indices is a []uint8, []uint16 or []uint32, or nil for a
+// mesh which is not indexed.
+func NewMesh(mode uint32, vertices interface{}, indices interface{}) (*Mesh, error) {
+	v := reflect.ValueOf(vertices)
+	if v.Kind() != reflect.Slice || v.Len() == 0 {
+		return nil, fmt.Errorf("vertices must be a non-empty slice, not %T", vertices)
+	}
+	layout, err := NewVertexLayout(reflect.Zero(v.Type().Elem()).Interface())
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Mesh{Mode: mode, NumVertices: int32(v.Len())}
+	if err := m.setIndices(indices); err != nil {
+		return nil, err
+	}
+	GenVertexArrays(1, &m.VAO)
+	gl.BindVertexArray(m.VAO)
+	GenBuffers(1, &m.VBO)
+	gl.BindBuffer(gl.ARRAY_BUFFER, m.VBO)
+	gl.BufferData(gl.ARRAY_BUFFER, v.Len()*layout.Stride, gl.Ptr(vertices), gl.STATIC_DRAW)
+	layout.Interleaved(m.VBO)
+	m.bufferIndices(indices)
+	return m, nil
+}
+
+// NewMeshSeparate creates a mesh drawing mode primitives, where each
+// attribute of vertex, a struct tagged as described for VertexLayout, is
+// read from its own slice in attributes, given in field order.  The slices
+// are packed one after the other into a single buffer.  Indices are as for
+// NewMesh.
+func NewMeshSeparate(mode uint32, vertex interface{}, attributes []interface{}, indices interface{}) (*Mesh, error) {
+	layout, err := NewVertexLayout(vertex)
+	if err != nil {
+		return nil, err
+	}
+	if len(attributes) != len(layout.Attributes) {
+		return nil, fmt.Errorf("layout has %d attributes, got %d slices", len(layout.Attributes), len(attributes))
+	}
+
+	m := &Mesh{Mode: mode}
+	buffers := make([]uint32, len(attributes))
+	offsets := make([]int, len(attributes))
+	size := 0
+	for i, a := range layout.Attributes {
+		v := reflect.ValueOf(attributes[i])
+		if v.Kind() != reflect.Slice || v.Len() == 0 {
+			return nil, fmt.Errorf("%s: attributes must be non-empty slices, not %T", a.Name, attributes[i])
+		}
+		n := v.Len() * int(v.Type().Elem().Size())
+		if n%a.bytes != 0 {
+			return nil, fmt.Errorf("%s: %d bytes is not a whole number of %d byte attributes", a.Name, n, a.bytes)
+		}
+		if i == 0 {
+			m.NumVertices = int32(n / a.bytes)
+		} else if int32(n/a.bytes) != m.NumVertices {
+			return nil, fmt.Errorf("%s: has %d vertices, %s has %d", a.Name, n/a.bytes, layout.Attributes[0].Name, m.NumVertices)
+		}
+		offsets[i] = size
+		size += n
+	}
+	if err := m.setIndices(indices); err != nil {
+		return nil, err
+	}
+
+	GenVertexArrays(1, &m.VAO)
+	gl.BindVertexArray(m.VAO)
+	GenBuffers(1, &m.VBO)
+	gl.BindBuffer(gl.ARRAY_BUFFER, m.VBO)
+	gl.BufferData(gl.ARRAY_BUFFER, size, nil, gl.STATIC_DRAW)
+	for i := range attributes {
+		buffers[i] = m.VBO
+		end := size
+		if i+1 < len(offsets) {
+			end = offsets[i+1]
+		}
+		gl.BufferSubData(gl.ARRAY_BUFFER, offsets[i], end-offsets[i], gl.Ptr(attributes[i]))
+	}
+	if err := layout.Separate(buffers, offsets); err != nil {
+		m.Delete()
+		return nil, err
+	}
+	m.bufferIndices(indices)
+	return m, nil
+}
+
+// setIndices sets the index count and type from indices.
+func (m *Mesh) setIndices(indices interface{}) error {
+	if indices == nil {
+		return nil
+	}
+	it, ok := indexTypes[reflect.TypeOf(indices)]
+	if !ok {
+		return fmt.Errorf("indices must be []uint8, []uint16 or []uint32, not %T", indices)
+	}
+	m.IndexType = it.glType
+	m.NumIndices = int32(reflect.ValueOf(indices).Len())
+	return nil
+}
+
+// bufferIndices creates the EBO holding indices, with the VAO bound so it
+// remembers the EBO.
+func (m *Mesh) bufferIndices(indices interface{}) {
+	if m.NumIndices == 0 {
+		return
+	}
+	GenBuffers(1, &m.EBO)
+	gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, m.EBO)
+	gl.BufferData(gl.ELEMENT_ARRAY_BUFFER, int(m.NumIndices)*m.indexSize(), gl.Ptr(indices), gl.STATIC_DRAW)
+}
+
+// indexSize returns the size in bytes of an index.
+func (m *Mesh) indexSize() int {
+	switch m.IndexType {
+	case gl.UNSIGNED_BYTE:
+		return 1
+	case gl.UNSIGNED_SHORT:
+		return 2
+	default:
+		return 4
+	}
+}
+
+// Draw the whole mesh, with glDrawElements if it is indexed and
+// glDrawArrays if not.
+func (m *Mesh) Draw() {
+	gl.BindVertexArray(m.VAO)
+	if m.EBO != 0 {
+		gl.DrawElements(m.Mode, m.NumIndices, m.IndexType, nil)
+	} else {
+		gl.DrawArrays(m.Mode, 0, m.NumVertices)
+	}
+}
+
+// DrawInstanced draws instances copies of the whole mesh, with
+// glDrawElementsInstanced if it is indexed and glDrawArraysInstanced if not.
+func (m *Mesh) DrawInstanced(instances int32) {
+	gl.BindVertexArray(m.VAO)
+	if m.EBO != 0 {
+		gl.DrawElementsInstanced(m.Mode, m.NumIndices, m.IndexType, nil, instances)
+	} else {
+		gl.DrawArraysInstanced(m.Mode, 0, m.NumVertices, instances)
+	}
+}
+
+// DrawBaseVertex draws the whole mesh with base added to every index, using
+// glDrawElementsBaseVertex.  A mesh which is not indexed draws from vertex
+// base to the end instead.
+func (m *Mesh) DrawBaseVertex(base int32) {
+	gl.BindVertexArray(m.VAO)
+	if m.EBO != 0 {
+		gl.DrawElementsBaseVertex(m.Mode, m.NumIndices, m.IndexType, nil, base)
+	} else {
+		gl.DrawArrays(m.Mode, base, m.NumVertices-base)
+	}
+}
+
+// DrawRange draws count indices starting at index first, using
+// glDrawRangeElements.  A mesh which is not indexed draws count vertices
+// starting at vertex first instead.
+func (m *Mesh) DrawRange(first, count int32) {
+	gl.BindVertexArray(m.VAO)
+	if m.EBO != 0 {
+		gl.DrawRangeElements(m.Mode, 0, uint32(m.NumVertices-1), count, m.IndexType, gl.PtrOffset(int(first)*m.indexSize()))
+	} else {
+		gl.DrawArrays(m.Mode, first, count)
+	}
+}
+
+// DrawArrays draws count vertices starting at vertex first in the order they
+// are stored, ignoring any indices.
+func (m *Mesh) DrawArrays(first, count int32) {
+	gl.BindVertexArray(m.VAO)
+	gl.DrawArrays(m.Mode, first, count)
+}
+
+// DrawArraysInstanced draws instances copies of count vertices starting at
+// vertex first, ignoring any indices.
+func (m *Mesh) DrawArraysInstanced(first, count, instances int32) {
+	gl.BindVertexArray(m.VAO)
+	gl.DrawArraysInstanced(m.Mode, first, count, instances)
+}
+
+// Delete the VAO and buffers of the mesh.
+func (m *Mesh) Delete() {
+	if m.EBO != 0 {
+		DeleteBuffers(1, &m.EBO)
+	}
+	DeleteBuffers(1, &m.VBO)
+	DeleteVertexArrays(1, &m.VAO)
+	*m = Mesh{}
+}
@@ -0,0 +1,173 @@
+package util
+
+import (
+	"unsafe"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// objectSet holds one kind of GL object created through this package which
+// has not been deleted yet.
+type objectSet struct {
+	// kind of object, as printed in leak reports.
+	kind string
+	// ids of the objects, mapped to the stack each was created from when
+	// leak tracking is enabled.
+	ids map[uint32]string
+}
+
+// newObjectSet returns an empty set of kind objects.
+func newObjectSet(kind string) *objectSet {
+	return &objectSet{kind: kind, ids: map[uint32]string{}}
+}
+
+// add id to the set, recording the stack which created it if leaks are
+// being tracked.
+func (s *objectSet) add(id uint32) {
+	var stack string
+	if leakMode != LeaksIgnore {
+		stack = callers()
+	}
+	s.ids[id] = stack
+}
+
+// remove id from the set.
+func (s *objectSet) remove(id uint32) {
+	delete(s.ids, id)
+}
+
+// objects created through this package which have not been deleted yet.
+// Run deletes anything left in here once the App has been closed.
+var objects = struct {
+	programs     *objectSet
+	shaders      *objectSet
+	pipelines    *objectSet
+	buffers      *objectSet
+	vaos         *objectSet
+	textures     *objectSet
+	framebuffers *objectSet
+}{
+	programs:     newObjectSet("program"),
+	shaders:      newObjectSet("shader"),
+	pipelines:    newObjectSet("program pipeline"),
+	buffers:      newObjectSet("buffer"),
+	vaos:         newObjectSet("vertex array"),
+	textures:     newObjectSet("texture"),
+	framebuffers: newObjectSet("framebuffer"),
+}
+
+// GenBuffers is gl.GenBuffers, with the buffers released by Run if they are
+// not deleted with DeleteBuffers.
+func GenBuffers(n int32, buffers *uint32) {
+	gl.GenBuffers(n, buffers)
+	for _, id := range unsafe.Slice(buffers, n) {
+		objects.buffers.add(id)
+	}
+}
+
+// DeleteBuffers is gl.DeleteBuffers for buffers created with GenBuffers.
+func DeleteBuffers(n int32, buffers *uint32) {
+	for _, id := range unsafe.Slice(buffers, n) {
+		objects.buffers.remove(id)
+	}
+	gl.DeleteBuffers(n, buffers)
+}
+
+// GenVertexArrays is gl.GenVertexArrays, with the VAOs released by Run if
+// they are not deleted with DeleteVertexArrays.
+func GenVertexArrays(n int32, arrays *uint32) {
+	gl.GenVertexArrays(n, arrays)
+	for _, id := range unsafe.Slice(arrays, n) {
+		objects.vaos.add(id)
+	}
+}
+
+// DeleteVertexArrays is gl.DeleteVertexArrays for VAOs created with
+// GenVertexArrays.
+func DeleteVertexArrays(n int32, arrays *uint32) {
+	for _, id := range unsafe.Slice(arrays, n) {
+		objects.vaos.remove(id)
+	}
+	gl.DeleteVertexArrays(n, arrays)
+}
+
+// GenTextures is gl.GenTextures, with the textures released by Run if they
+// are not deleted with DeleteTextures.
+func GenTextures(n int32, textures *uint32) {
+	gl.GenTextures(n, textures)
+	for _, id := range unsafe.Slice(textures, n) {
+		objects.textures.add(id)
+	}
+}
+
+// DeleteTextures is gl.DeleteTextures for textures created with
+// GenTextures.
+func DeleteTextures(n int32, textures *uint32) {
+	for _, id := range unsafe.Slice(textures, n) {
+		objects.textures.remove(id)
+	}
+	gl.DeleteTextures(n, textures)
+}
+
+// GenFramebuffers is gl.GenFramebuffers, with the framebuffers released by
+// Run if they are not deleted with DeleteFramebuffers.
+func GenFramebuffers(n int32, framebuffers *uint32) {
+	gl.GenFramebuffers(n, framebuffers)
+	for _, id := range unsafe.Slice(framebuffers, n) {
+		objects.framebuffers.add(id)
+	}
+}
+
+// DeleteFramebuffers is gl.DeleteFramebuffers for framebuffers created with
+// GenFramebuffers.
+func DeleteFramebuffers(n int32, framebuffers *uint32) {
+	for _, id := range unsafe.Slice(framebuffers, n) {
+		objects.framebuffers.remove(id)
+	}
+	gl.DeleteFramebuffers(n, framebuffers)
+}
+
+// deleteProgram is gl.DeleteProgram for programs created by Load.
+func deleteProgram(program uint32) {
+	objects.programs.remove(program)
+	gl.DeleteProgram(program)
+}
+
+// deleteObjects deletes every object still tracked.  When leaks are being
+// tracked, each object deleted here is recorded as a leak first.
+func deleteObjects() {
+	recordLeaks()
+
+	for p := range reloaders {
+		delete(reloaders, p)
+	}
+	for name := range uniformBuffers {
+		delete(uniformBuffers, name)
+	}
+	gl.UseProgram(0)
+	gl.BindProgramPipeline(0)
+	for id := range objects.pipelines.ids {
+		objects.pipelines.remove(id)
+		gl.DeleteProgramPipelines(1, &id)
+	}
+	for id := range objects.programs.ids {
+		deleteProgram(id)
+	}
+	for id := range objects.shaders.ids {
+		objects.shaders.remove(id)
+		gl.DeleteShader(id)
+	}
+	gl.BindVertexArray(0)
+	for id := range objects.vaos.ids {
+		DeleteVertexArrays(1, &id)
+	}
+	for id := range objects.buffers.ids {
+		DeleteBuffers(1, &id)
+	}
+	for id := range objects.textures.ids {
+		DeleteTextures(1, &id)
+	}
+	for id := range objects.framebuffers.ids {
+		DeleteFramebuffers(1, &id)
+	}
+}
@@ -0,0 +1,140 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// ProgramPipeline combines separable programs, each providing some of the
+// stages, so that a stage can be swapped without relinking the others.
+type ProgramPipeline struct {
+	// ID of the GL program pipeline.
+	ID uint32
+
+	// stages maps each stage bit to the program providing it.
+	stages map[uint32]*Program
+	// used maps each stage bit to the program ID last handed to GL, so
+	// programs rebuilt by hot reload can be put back in place.
+	used map[uint32]uint32
+}
+
+// NewProgramPipeline creates an empty pipeline, which Run deletes if it is
+// not deleted with Delete.
+func NewProgramPipeline() *ProgramPipeline {
+	p := &ProgramPipeline{
+		stages: map[uint32]*Program{},
+		used:   map[uint32]uint32{},
+	}
+	gl.GenProgramPipelines(1, &p.ID)
+	objects.pipelines.add(p.ID)
+	return p
+}
+
+// UseProgram uses prog for every stage it has a shader for.
+func (p *ProgramPipeline) UseProgram(prog *Program) error {
+	return p.UseStages(prog.Stages, prog)
+}
+
+// UseStages uses prog for the given stages, a mask of gl.VERTEX_SHADER_BIT,
+// gl.FRAGMENT_SHADER_BIT and so on.  A nil prog clears the stages.
+func (p *ProgramPipeline) UseStages(stages uint32, prog *Program) error {
+	if prog != nil {
+		if !prog.Separable {
+			return fmt.Errorf("program %d was not loaded with LoadSeparable", prog.ID)
+		}
+		if missing := stages &^ prog.Stages; missing != 0 {
+			return fmt.Errorf("program %d has no shader for %s", prog.ID, stageNames(missing))
+		}
+	}
+
+	for _, stage := range stageBits {
+		if stages&stage == 0 {
+			continue
+		}
+		if prog == nil {
+			delete(p.stages, stage)
+			delete(p.used, stage)
+		} else {
+			p.stages[stage] = prog
+			p.used[stage] = prog.ID
+		}
+	}
+
+	var id uint32
+	if prog != nil {
+		id = prog.ID
+	}
+	gl.UseProgramStages(p.ID, stages, id)
+	return nil
+}
+
+// Program returns the program used for a stage, nil if there is none.
+func (p *ProgramPipeline) Program(stage uint32) *Program {
+	return p.stages[stage]
+}
+
+// Bind the pipeline for rendering.  Any program made current with
+// Program.Use takes precedence over a pipeline, so that is cleared first.
+func (p *ProgramPipeline) Bind() {
+	for stage, prog := range p.stages {
+		if prog.ID != p.used[stage] {
+			gl.UseProgramStages(p.ID, stage, prog.ID)
+			p.used[stage] = prog.ID
+		}
+	}
+	gl.UseProgram(0)
+	gl.BindProgramPipeline(p.ID)
+}
+
+// Validate checks the pipeline can be used with the current GL state,
+// returning the info log if it can not.
+func (p *ProgramPipeline) Validate() error {
+	gl.ValidateProgramPipeline(p.ID)
+	var valid int32
+	if gl.GetProgramPipelineiv(p.ID, gl.VALIDATE_STATUS, &valid); valid == gl.FALSE {
+		return fmt.Errorf("program pipeline %d is not valid: %s", p.ID, p.InfoLog())
+	}
+	return nil
+}
+
+// InfoLog returns the info log of the pipeline.
+func (p *ProgramPipeline) InfoLog() string {
+	var l int32
+	gl.GetProgramPipelineiv(p.ID, gl.INFO_LOG_LENGTH, &l)
+	if l == 0 {
+		return ""
+	}
+	msg := strings.Repeat("\x00", int(l+1))
+	gl.GetProgramPipelineInfoLog(p.ID, l, nil, gl.Str(msg))
+	return strings.TrimRight(msg, "\x00")
+}
+
+// Delete the pipeline.  The programs it uses are not deleted.
+func (p *ProgramPipeline) Delete() {
+	objects.pipelines.remove(p.ID)
+	gl.DeleteProgramPipelines(1, &p.ID)
+	p.ID = 0
+}
+
+// stageNames returns the names of the stages in a mask, for errors.
+func stageNames(stages uint32) string {
+	names := []struct {
+		bit  uint32
+		name string
+	}{
+		{gl.VERTEX_SHADER_BIT, "vertex"},
+		{gl.TESS_CONTROL_SHADER_BIT, "tessellation control"},
+		{gl.TESS_EVALUATION_SHADER_BIT, "tessellation evaluation"},
+		{gl.GEOMETRY_SHADER_BIT, "geometry"},
+		{gl.FRAGMENT_SHADER_BIT, "fragment"},
+	}
+	var s []string
+	for _, n := range names {
+		if stages&n.bit != 0 {
+			s = append(s, n.name)
+		}
+	}
+	return strings.Join(s, ", ")
+}
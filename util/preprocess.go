@@ -0,0 +1,175 @@
+package util
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+)
+
+// sourceFile is one of the files a shader was built from.
+type sourceFile struct {
+	name string
+	text []byte
+}
+
+// preprocessor expands #include directives and injects #defines, keeping
+// track of which file each line came from.  Every file is given a source
+// string number, and #line directives are inserted so the compiler reports
+// errors against the original file and line.
+type preprocessor struct {
+	// includePaths searched for #include <name>, and for #include "name"
+	// when it is not found next to the including file.
+	includePaths []string
+	// defines injected after the #version line.
+	defines map[string]string
+	// fsys the files are read from.
+	fsys fs.FS
+
+	// files indexed by their source string number.
+	files []sourceFile
+	// once lists the files whose #pragma once directive has been reached.
+	once map[string]bool
+	// stack of files currently being expanded, to catch include cycles.
+	stack []string
+}
+
+// preprocess filename from fsys, returning the expanded source and the files
+// it was built from, indexed by source string number.
+func preprocess(fsys fs.FS, filename string, defines map[string]string, includePaths []string) (string, []sourceFile, error) {
+	p := &preprocessor{
+		includePaths: includePaths,
+		defines:      defines,
+		fsys:         fsys,
+		once:         map[string]bool{},
+	}
+
+	out := new(bytes.Buffer)
+	if err := p.expand(filename, out); err != nil {
+		return "", nil, err
+	}
+	return out.String(), p.files, nil
+}
+
+// expand filename into out.
+func (p *preprocessor) expand(filename string, out *bytes.Buffer) error {
+	if p.once[filename] {
+		return nil
+	}
+	for _, f := range p.stack {
+		if f == filename {
+			return fmt.Errorf("include cycle: %s -> %s", strings.Join(p.stack, " -> "), filename)
+		}
+	}
+
+	data, err := fs.ReadFile(p.fsys, filename)
+	if err != nil {
+		return err
+	}
+
+	p.stack = append(p.stack, filename)
+	defer func() { p.stack = p.stack[:len(p.stack)-1] }()
+
+	source := len(p.files)
+	p.files = append(p.files, sourceFile{name: filename, text: data})
+	root := source == 0
+	switch {
+	case !root:
+		fmt.Fprintf(out, "#line 1 %d\n", source)
+	case !bytes.Contains(data, []byte("#version")):
+		// No #version to put the defines after, so they go first.
+		p.writeDefines(out)
+		fmt.Fprintf(out, "#line 1 %d\n", source)
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		directive := strings.Fields(strings.TrimPrefix(strings.TrimSpace(text), "#"))
+		switch {
+		case !strings.HasPrefix(strings.TrimSpace(text), "#") || len(directive) == 0:
+			out.WriteString(text)
+			out.WriteByte('\n')
+
+		case directive[0] == "version":
+			if !root {
+				return fmt.Errorf("%s:%d: #version in included file", filename, line)
+			}
+			out.WriteString(text)
+			out.WriteByte('\n')
+			p.writeDefines(out)
+			fmt.Fprintf(out, "#line %d %d\n", line+1, source)
+
+		case directive[0] == "pragma" && len(directive) > 1 && directive[1] == "once":
+			p.once[filename] = true
+			out.WriteByte('\n')
+
+		case directive[0] == "include":
+			name := strings.TrimPrefix(strings.TrimSpace(text)[1:], "include")
+			name = strings.TrimSpace(stripComment(name))
+			included, err := p.resolve(filename, name)
+			if err != nil {
+				return fmt.Errorf("%s:%d: %s", filename, line, err)
+			}
+			if err := p.expand(included, out); err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "#line %d %d\n", line+1, source)
+
+		default:
+			out.WriteString(text)
+			out.WriteByte('\n')
+		}
+	}
+	return scanner.Err()
+}
+
+// resolve the argument of an #include found in filename to the file it names.
+func (p *preprocessor) resolve(filename, name string) (string, error) {
+	var dirs []string
+	switch {
+	case len(name) > 2 && name[0] == '"' && name[len(name)-1] == '"':
+		dirs = append([]string{path.Dir(filename)}, p.includePaths...)
+	case len(name) > 2 && name[0] == '<' && name[len(name)-1] == '>':
+		dirs = p.includePaths
+	default:
+		return "", fmt.Errorf("#include expects \"FILENAME\" or <FILENAME>, got %s", name)
+	}
+	name = name[1 : len(name)-1]
+
+	for _, dir := range dirs {
+		candidate := path.Join(dir, name)
+		if _, err := fs.Stat(p.fsys, candidate); err == nil {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("%s not found", name)
+}
+
+// stripComment removes a trailing // or /* */ comment from the rest of a
+// directive line.
+func stripComment(s string) string {
+	if i := strings.Index(s, "//"); i >= 0 {
+		s = s[:i]
+	}
+	if i := strings.Index(s, "/*"); i >= 0 {
+		s = s[:i]
+	}
+	return s
+}
+
+// writeDefines to out in name order, so the output does not depend on map
+// iteration order.
+func (p *preprocessor) writeDefines(out *bytes.Buffer) {
+	names := make([]string, 0, len(p.defines))
+	for name := range p.defines {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(out, "#define %s %s\n", name, p.defines[name])
+	}
+}
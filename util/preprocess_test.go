@@ -0,0 +1,253 @@
+package util
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestPreprocess(t *testing.T) {
+	tests := []struct {
+		name         string
+		files        map[string]string
+		filename     string
+		defines      map[string]string
+		includePaths []string
+		// want is the expanded source, and wantFiles the names of the
+		// source strings in order.
+		want      string
+		wantFiles []string
+		// wantErr is part of the expected error, if any.
+		wantErr string
+	}{
+		{
+			name: "defines after version",
+			files: map[string]string{
+				"main.vert": "#version 410 core\nvoid main() {}\n",
+			},
+			filename: "main.vert",
+			defines:  map[string]string{"B": "2", "A": "1"},
+			want: "#version 410 core\n" +
+				"#define A 1\n" +
+				"#define B 2\n" +
+				"#line 2 0\n" +
+				"void main() {}\n",
+			wantFiles: []string{"main.vert"},
+		},
+		{
+			name: "defines without version",
+			files: map[string]string{
+				"main.vert": "void main() {}\n",
+			},
+			filename: "main.vert",
+			defines:  map[string]string{"A": "1"},
+			want: "#define A 1\n" +
+				"#line 1 0\n" +
+				"void main() {}\n",
+			wantFiles: []string{"main.vert"},
+		},
+		{
+			name: "quoted include next to file",
+			files: map[string]string{
+				"shaders/main.vert":   "#version 410\n#include \"common.glsl\"\nvoid main() {}\n",
+				"shaders/common.glsl": "float f;\n",
+			},
+			filename: "shaders/main.vert",
+			want: "#version 410\n" +
+				"#line 2 0\n" +
+				"#line 1 1\n" +
+				"float f;\n" +
+				"#line 3 0\n" +
+				"void main() {}\n",
+			wantFiles: []string{"shaders/main.vert", "shaders/common.glsl"},
+		},
+		{
+			name: "quoted include falls back to include paths",
+			files: map[string]string{
+				"shaders/main.vert":   "#include \"common.glsl\"\n",
+				"include/common.glsl": "float f;\n",
+			},
+			filename:     "shaders/main.vert",
+			includePaths: []string{"include"},
+			want: "#line 1 0\n" +
+				"#line 1 1\n" +
+				"float f;\n" +
+				"#line 2 0\n",
+			wantFiles: []string{"shaders/main.vert", "include/common.glsl"},
+		},
+		{
+			name: "angle include from include paths",
+			files: map[string]string{
+				"main.vert":        "#include <lib/noise.glsl>\n",
+				"lib/noise.glsl":   "float wrong;\n",
+				"b/lib/noise.glsl": "float noise;\n",
+			},
+			filename:     "main.vert",
+			includePaths: []string{"a", "b"},
+			want: "#line 1 0\n" +
+				"#line 1 1\n" +
+				"float noise;\n" +
+				"#line 2 0\n",
+			wantFiles: []string{"main.vert", "b/lib/noise.glsl"},
+		},
+		{
+			name: "angle include ignores the including directory",
+			files: map[string]string{
+				"shaders/main.vert":   "#include <common.glsl>\n",
+				"shaders/common.glsl": "float f;\n",
+			},
+			filename: "shaders/main.vert",
+			wantErr:  "shaders/main.vert:1: common.glsl not found",
+		},
+		{
+			name: "malformed include",
+			files: map[string]string{
+				"main.vert": "#include common.glsl\n",
+			},
+			filename: "main.vert",
+			wantErr:  "main.vert:1: #include expects",
+		},
+		{
+			name: "pragma once",
+			files: map[string]string{
+				"main.vert": "#version 410\n#include \"a.glsl\"\n#include \"a.glsl\"\nvoid main() {}\n",
+				"a.glsl":    "#pragma once\nfloat a;\n",
+			},
+			filename: "main.vert",
+			want: "#version 410\n" +
+				"#line 2 0\n" +
+				"#line 1 1\n" +
+				"\n" +
+				"float a;\n" +
+				"#line 3 0\n" +
+				"#line 4 0\n" +
+				"void main() {}\n",
+			wantFiles: []string{"main.vert", "a.glsl"},
+		},
+		{
+			name: "pragma once in a comment is ignored",
+			files: map[string]string{
+				"main.vert": "#include \"a.glsl\"\n#include \"a.glsl\"\n",
+				"a.glsl":    "// no #pragma once here\nfloat a;\n",
+			},
+			filename: "main.vert",
+			want: "#line 1 0\n" +
+				"#line 1 1\n" +
+				"// no #pragma once here\n" +
+				"float a;\n" +
+				"#line 2 0\n" +
+				"#line 1 2\n" +
+				"// no #pragma once here\n" +
+				"float a;\n" +
+				"#line 3 0\n",
+			wantFiles: []string{"main.vert", "a.glsl", "a.glsl"},
+		},
+		{
+			name: "include with trailing comments",
+			files: map[string]string{
+				"main.vert":    "#include \"common.glsl\" // helpers\n#include <lib.glsl> /* more */\n",
+				"common.glsl":  "float f;\n",
+				"inc/lib.glsl": "float g;\n",
+			},
+			filename:     "main.vert",
+			includePaths: []string{"inc"},
+			want: "#line 1 0\n" +
+				"#line 1 1\n" +
+				"float f;\n" +
+				"#line 2 0\n" +
+				"#line 1 2\n" +
+				"float g;\n" +
+				"#line 3 0\n",
+			wantFiles: []string{"main.vert", "common.glsl", "inc/lib.glsl"},
+		},
+		{
+			name: "include without pragma once is repeated",
+			files: map[string]string{
+				"main.vert": "#include \"a.glsl\"\n#include \"a.glsl\"\n",
+				"a.glsl":    "float a;\n",
+			},
+			filename: "main.vert",
+			want: "#line 1 0\n" +
+				"#line 1 1\n" +
+				"float a;\n" +
+				"#line 2 0\n" +
+				"#line 1 2\n" +
+				"float a;\n" +
+				"#line 3 0\n",
+			wantFiles: []string{"main.vert", "a.glsl", "a.glsl"},
+		},
+		{
+			name: "include cycle",
+			files: map[string]string{
+				"main.vert": "#include \"a.glsl\"\n",
+				"a.glsl":    "#include \"b.glsl\"\n",
+				"b.glsl":    "#include \"a.glsl\"\n",
+			},
+			filename: "main.vert",
+			wantErr:  "include cycle: main.vert -> a.glsl -> b.glsl -> a.glsl",
+		},
+		{
+			name: "source string numbers",
+			files: map[string]string{
+				"main.vert": "#version 410\n#include \"a.glsl\"\n#include \"b.glsl\"\nvoid main() {}\n",
+				"a.glsl":    "float a;\n",
+				"b.glsl":    "#include \"c.glsl\"\nfloat b;\n",
+				"c.glsl":    "float c;\n",
+			},
+			filename: "main.vert",
+			want: "#version 410\n" +
+				"#line 2 0\n" +
+				"#line 1 1\n" +
+				"float a;\n" +
+				"#line 3 0\n" +
+				"#line 1 2\n" +
+				"#line 1 3\n" +
+				"float c;\n" +
+				"#line 2 2\n" +
+				"float b;\n" +
+				"#line 4 0\n" +
+				"void main() {}\n",
+			wantFiles: []string{"main.vert", "a.glsl", "b.glsl", "c.glsl"},
+		},
+		{
+			name: "version in included file",
+			files: map[string]string{
+				"main.vert": "#version 410\n#include \"a.glsl\"\n",
+				"a.glsl":    "float a;\n#version 410\n",
+			},
+			filename: "main.vert",
+			wantErr:  "a.glsl:2: #version in included file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys := fstest.MapFS{}
+			for name, text := range tt.files {
+				fsys[name] = &fstest.MapFile{Data: []byte(text)}
+			}
+
+			got, files, err := preprocess(fsys, tt.filename, tt.defines, tt.includePaths)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("source =\n%s\nwant\n%s", got, tt.want)
+			}
+			var names []string
+			for _, f := range files {
+				names = append(names, f.name)
+			}
+			if !reflect.DeepEqual(names, tt.wantFiles) {
+				t.Errorf("files = %q, want %q", names, tt.wantFiles)
+			}
+		})
+	}
+}
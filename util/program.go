@@ -0,0 +1,418 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Program is a linked GL program along with the attributes, uniforms and
+// uniform blocks it uses.  Uniforms are set by name, with the locations
+// looked up once when the program is linked.
+type Program struct {
+	// ID of the GL program.
+	ID uint32
+	// Stages the program has shaders for, as a mask of gl.VERTEX_SHADER_BIT,
+	// gl.FRAGMENT_SHADER_BIT and so on.
+	Stages uint32
+	// Separable programs were loaded with LoadSeparable, and can be used in
+	// a ProgramPipeline.
+	Separable bool
+	// Attributes active in the program by name.
+	Attributes map[string]Variable
+	// Uniforms active in the program by name.  Arrays can be found both as
+	// "name" and "name[0]".
+	Uniforms map[string]Variable
+	// UniformBlocks active in the program by name.
+	UniformBlocks map[string]UniformBlock
+	// Warnings reported when the program was linked.  Programs loaded from
+	// the cache have none.
+	Warnings []Diagnostic
+
+	// OnReload is called after a program loaded with LoadReloadable has been
+	// rebuilt.  Uniform values do not survive a relink, so anything not set
+	// every frame should be set again here.
+	OnReload func(p *Program)
+
+	// warned lists the names a warning has already been printed for.
+	warned map[string]bool
+}
+
+// Variable is an active attribute or uniform.
+type Variable struct {
+	Name string
+	// Location to pass to gl calls, -1 for uniforms in a block.
+	Location int32
+	// Type such as gl.FLOAT_VEC4.
+	Type uint32
+	// Size is the length of the array, 1 if it is not an array.
+	Size int32
+}
+
+// UniformBlock is an active uniform block.
+type UniformBlock struct {
+	Name string
+	// Index of the block within the program.
+	Index uint32
+	// DataSize is the size in bytes of the buffer needed to back the block.
+	DataSize int32
+	// Binding point the block reads from.
+	Binding uint32
+	// Uniforms in the block, with their layout.
+	Uniforms []BlockUniform
+}
+
+// BlockUniform is an active uniform within a uniform block.
+type BlockUniform struct {
+	Variable
+	// Offset in bytes from the start of the block.
+	Offset int32
+	// ArrayStride is the distance in bytes between array elements, 0 if it
+	// is not an array.
+	ArrayStride int32
+	// MatrixStride is the distance in bytes between matrix columns, 0 if it
+	// is not a matrix.
+	MatrixStride int32
+}
+
+// newProgram reflects over the linked program id.
+func newProgram(id uint32, stages uint32, separable bool) *Program {
+	p := &Program{
+		ID:            id,
+		Stages:        stages,
+		Separable:     separable,
+		Attributes:    map[string]Variable{},
+		Uniforms:      map[string]Variable{},
+		UniformBlocks: map[string]UniformBlock{},
+		warned:        map[string]bool{},
+	}
+
+	var n, maxLength int32
+	gl.GetProgramiv(id, gl.ACTIVE_ATTRIBUTES, &n)
+	gl.GetProgramiv(id, gl.ACTIVE_ATTRIBUTE_MAX_LENGTH, &maxLength)
+	name := make([]uint8, maxLength+1)
+	for i := uint32(0); i < uint32(n); i++ {
+		var length, size int32
+		var xtype uint32
+		gl.GetActiveAttrib(id, i, int32(len(name)), &length, &size, &xtype, &name[0])
+		v := Variable{
+			Name:     string(name[:length]),
+			Location: gl.GetAttribLocation(id, &name[0]),
+			Type:     xtype,
+			Size:     size,
+		}
+		p.Attributes[v.Name] = v
+	}
+
+	gl.GetProgramiv(id, gl.ACTIVE_UNIFORMS, &n)
+	gl.GetProgramiv(id, gl.ACTIVE_UNIFORM_MAX_LENGTH, &maxLength)
+	name = make([]uint8, maxLength+1)
+	uniforms := make([]Variable, n)
+	for i := uint32(0); i < uint32(n); i++ {
+		var length, size int32
+		var xtype uint32
+		gl.GetActiveUniform(id, i, int32(len(name)), &length, &size, &xtype, &name[0])
+		v := Variable{
+			Name:     string(name[:length]),
+			Location: gl.GetUniformLocation(id, &name[0]),
+			Type:     xtype,
+			Size:     size,
+		}
+		uniforms[i] = v
+		p.Uniforms[v.Name] = v
+		if strings.HasSuffix(v.Name, "[0]") {
+			p.Uniforms[strings.TrimSuffix(v.Name, "[0]")] = v
+		}
+	}
+
+	gl.GetProgramiv(id, gl.ACTIVE_UNIFORM_BLOCKS, &n)
+	gl.GetProgramiv(id, gl.ACTIVE_UNIFORM_BLOCK_MAX_NAME_LENGTH, &maxLength)
+	name = make([]uint8, maxLength+1)
+	for i := uint32(0); i < uint32(n); i++ {
+		var length, dataSize, binding int32
+		gl.GetActiveUniformBlockName(id, i, int32(len(name)), &length, &name[0])
+		gl.GetActiveUniformBlockiv(id, i, gl.UNIFORM_BLOCK_DATA_SIZE, &dataSize)
+		gl.GetActiveUniformBlockiv(id, i, gl.UNIFORM_BLOCK_BINDING, &binding)
+		b := UniformBlock{
+			Name:     string(name[:length]),
+			Index:    i,
+			DataSize: dataSize,
+			Binding:  uint32(binding),
+		}
+
+		var count int32
+		gl.GetActiveUniformBlockiv(id, i, gl.UNIFORM_BLOCK_ACTIVE_UNIFORMS, &count)
+		if count > 0 {
+			indices := make([]int32, count)
+			gl.GetActiveUniformBlockiv(id, i, gl.UNIFORM_BLOCK_ACTIVE_UNIFORM_INDICES, &indices[0])
+			members := make([]uint32, count)
+			for j, index := range indices {
+				members[j] = uint32(index)
+			}
+			offsets := make([]int32, count)
+			arrayStrides := make([]int32, count)
+			matrixStrides := make([]int32, count)
+			gl.GetActiveUniformsiv(id, count, &members[0], gl.UNIFORM_OFFSET, &offsets[0])
+			gl.GetActiveUniformsiv(id, count, &members[0], gl.UNIFORM_ARRAY_STRIDE, &arrayStrides[0])
+			gl.GetActiveUniformsiv(id, count, &members[0], gl.UNIFORM_MATRIX_STRIDE, &matrixStrides[0])
+			for j, index := range members {
+				if int(index) >= len(uniforms) {
+					continue
+				}
+				b.Uniforms = append(b.Uniforms, BlockUniform{
+					Variable:     uniforms[index],
+					Offset:       offsets[j],
+					ArrayStride:  arrayStrides[j],
+					MatrixStride: matrixStrides[j],
+				})
+			}
+		}
+		p.UniformBlocks[b.Name] = b
+	}
+
+	return p
+}
+
+// Use the program for rendering.
+func (p *Program) Use() {
+	gl.UseProgram(p.ID)
+}
+
+// Delete the program.  Programs loaded with LoadReloadable stop being
+// watched for changes.
+func (p *Program) Delete() {
+	delete(reloaders, p)
+	deleteProgram(p.ID)
+	p.ID = 0
+}
+
+// AttribLocation returns the location of the named attribute.
+func (p *Program) AttribLocation(name string) (uint32, error) {
+	v, ok := p.Attributes[name]
+	if !ok {
+		return 0, fmt.Errorf("program %d has no active attribute %q", p.ID, name)
+	}
+	return uint32(v.Location), nil
+}
+
+// SetInt sets an int, bool, sampler or image uniform.
+func (p *Program) SetInt(name string, value int32) error {
+	types := []uint32{gl.INT, gl.BOOL}
+	if v, ok := p.Uniforms[name]; ok && isSampler(v.Type) {
+		types = append(types, v.Type)
+	}
+	v, err := p.uniform(name, types...)
+	if err != nil {
+		return err
+	}
+	gl.ProgramUniform1i(p.ID, v.Location, value)
+	return nil
+}
+
+// SetUint sets a uint uniform.
+func (p *Program) SetUint(name string, value uint32) error {
+	v, err := p.uniform(name, gl.UNSIGNED_INT)
+	if err != nil {
+		return err
+	}
+	gl.ProgramUniform1ui(p.ID, v.Location, value)
+	return nil
+}
+
+// SetFloat sets a float uniform.
+func (p *Program) SetFloat(name string, value float32) error {
+	v, err := p.uniform(name, gl.FLOAT)
+	if err != nil {
+		return err
+	}
+	gl.ProgramUniform1f(p.ID, v.Location, value)
+	return nil
+}
+
+// SetVec2 sets a vec2 uniform.
+func (p *Program) SetVec2(name string, value mgl32.Vec2) error {
+	v, err := p.uniform(name, gl.FLOAT_VEC2)
+	if err != nil {
+		return err
+	}
+	gl.ProgramUniform2fv(p.ID, v.Location, 1, &value[0])
+	return nil
+}
+
+// SetVec3 sets a vec3 uniform.
+func (p *Program) SetVec3(name string, value mgl32.Vec3) error {
+	v, err := p.uniform(name, gl.FLOAT_VEC3)
+	if err != nil {
+		return err
+	}
+	gl.ProgramUniform3fv(p.ID, v.Location, 1, &value[0])
+	return nil
+}
+
+// SetVec4 sets a vec4 uniform.
+func (p *Program) SetVec4(name string, value mgl32.Vec4) error {
+	v, err := p.uniform(name, gl.FLOAT_VEC4)
+	if err != nil {
+		return err
+	}
+	gl.ProgramUniform4fv(p.ID, v.Location, 1, &value[0])
+	return nil
+}
+
+// SetMat3 sets a mat3 uniform.
+func (p *Program) SetMat3(name string, value mgl32.Mat3) error {
+	v, err := p.uniform(name, gl.FLOAT_MAT3)
+	if err != nil {
+		return err
+	}
+	gl.ProgramUniformMatrix3fv(p.ID, v.Location, 1, false, &value[0])
+	return nil
+}
+
+// SetMat4 sets a mat4 uniform.
+func (p *Program) SetMat4(name string, value mgl32.Mat4) error {
+	v, err := p.uniform(name, gl.FLOAT_MAT4)
+	if err != nil {
+		return err
+	}
+	gl.ProgramUniformMatrix4fv(p.ID, v.Location, 1, false, &value[0])
+	return nil
+}
+
+// uniform returns the named uniform, checking it has one of the types given.
+// Setters are usually called every frame, so a warning is printed the first
+// time a name fails and the error is returned every time.
+func (p *Program) uniform(name string, types ...uint32) (Variable, error) {
+	v, ok := p.Uniforms[name]
+	var err error
+	switch {
+	case !ok:
+		err = fmt.Errorf("program %d has no active uniform %q", p.ID, name)
+	case v.Location < 0:
+		err = fmt.Errorf("uniform %q is in a uniform block", name)
+	default:
+		for _, t := range types {
+			if v.Type == t {
+				return v, nil
+			}
+		}
+		err = fmt.Errorf("uniform %q is a %s, not a %s", name, typeName(v.Type), typeName(types[0]))
+	}
+
+	if !p.warned[name] {
+		p.warned[name] = true
+		logger.Println("Warning:", err)
+	}
+	return v, err
+}
+
+// typeNames of the GLSL types returned by reflection.
+var typeNames = map[uint32]string{
+	gl.FLOAT:                                     "float",
+	gl.FLOAT_VEC2:                                "vec2",
+	gl.FLOAT_VEC3:                                "vec3",
+	gl.FLOAT_VEC4:                                "vec4",
+	gl.DOUBLE:                                    "double",
+	gl.INT:                                       "int",
+	gl.INT_VEC2:                                  "ivec2",
+	gl.INT_VEC3:                                  "ivec3",
+	gl.INT_VEC4:                                  "ivec4",
+	gl.UNSIGNED_INT:                              "uint",
+	gl.UNSIGNED_INT_VEC2:                         "uvec2",
+	gl.UNSIGNED_INT_VEC3:                         "uvec3",
+	gl.UNSIGNED_INT_VEC4:                         "uvec4",
+	gl.BOOL:                                      "bool",
+	gl.FLOAT_MAT2:                                "mat2",
+	gl.FLOAT_MAT3:                                "mat3",
+	gl.FLOAT_MAT4:                                "mat4",
+	gl.SAMPLER_1D:                                "sampler1D",
+	gl.SAMPLER_1D_SHADOW:                         "sampler1DShadow",
+	gl.SAMPLER_1D_ARRAY:                          "sampler1DArray",
+	gl.SAMPLER_1D_ARRAY_SHADOW:                   "sampler1DArrayShadow",
+	gl.SAMPLER_2D:                                "sampler2D",
+	gl.SAMPLER_2D_SHADOW:                         "sampler2DShadow",
+	gl.SAMPLER_2D_ARRAY:                          "sampler2DArray",
+	gl.SAMPLER_2D_ARRAY_SHADOW:                   "sampler2DArrayShadow",
+	gl.SAMPLER_2D_MULTISAMPLE:                    "sampler2DMS",
+	gl.SAMPLER_2D_MULTISAMPLE_ARRAY:              "sampler2DMSArray",
+	gl.SAMPLER_2D_RECT:                           "sampler2DRect",
+	gl.SAMPLER_2D_RECT_SHADOW:                    "sampler2DRectShadow",
+	gl.SAMPLER_3D:                                "sampler3D",
+	gl.SAMPLER_CUBE:                              "samplerCube",
+	gl.SAMPLER_CUBE_SHADOW:                       "samplerCubeShadow",
+	gl.SAMPLER_CUBE_MAP_ARRAY:                    "samplerCubeArray",
+	gl.SAMPLER_CUBE_MAP_ARRAY_SHADOW:             "samplerCubeArrayShadow",
+	gl.SAMPLER_BUFFER:                            "samplerBuffer",
+	gl.INT_SAMPLER_1D:                            "isampler1D",
+	gl.INT_SAMPLER_1D_ARRAY:                      "isampler1DArray",
+	gl.INT_SAMPLER_2D:                            "isampler2D",
+	gl.INT_SAMPLER_2D_ARRAY:                      "isampler2DArray",
+	gl.INT_SAMPLER_2D_MULTISAMPLE:                "isampler2DMS",
+	gl.INT_SAMPLER_2D_MULTISAMPLE_ARRAY:          "isampler2DMSArray",
+	gl.INT_SAMPLER_2D_RECT:                       "isampler2DRect",
+	gl.INT_SAMPLER_3D:                            "isampler3D",
+	gl.INT_SAMPLER_CUBE:                          "isamplerCube",
+	gl.INT_SAMPLER_CUBE_MAP_ARRAY:                "isamplerCubeArray",
+	gl.INT_SAMPLER_BUFFER:                        "isamplerBuffer",
+	gl.UNSIGNED_INT_SAMPLER_1D:                   "usampler1D",
+	gl.UNSIGNED_INT_SAMPLER_1D_ARRAY:             "usampler1DArray",
+	gl.UNSIGNED_INT_SAMPLER_2D:                   "usampler2D",
+	gl.UNSIGNED_INT_SAMPLER_2D_ARRAY:             "usampler2DArray",
+	gl.UNSIGNED_INT_SAMPLER_2D_MULTISAMPLE:       "usampler2DMS",
+	gl.UNSIGNED_INT_SAMPLER_2D_MULTISAMPLE_ARRAY: "usampler2DMSArray",
+	gl.UNSIGNED_INT_SAMPLER_2D_RECT:              "usampler2DRect",
+	gl.UNSIGNED_INT_SAMPLER_3D:                   "usampler3D",
+	gl.UNSIGNED_INT_SAMPLER_CUBE:                 "usamplerCube",
+	gl.UNSIGNED_INT_SAMPLER_CUBE_MAP_ARRAY:       "usamplerCubeArray",
+	gl.UNSIGNED_INT_SAMPLER_BUFFER:               "usamplerBuffer",
+	gl.IMAGE_1D:                                  "image1D",
+	gl.IMAGE_1D_ARRAY:                            "image1DArray",
+	gl.IMAGE_2D:                                  "image2D",
+	gl.IMAGE_2D_ARRAY:                            "image2DArray",
+	gl.IMAGE_2D_MULTISAMPLE:                      "image2DMS",
+	gl.IMAGE_2D_MULTISAMPLE_ARRAY:                "image2DMSArray",
+	gl.IMAGE_2D_RECT:                             "image2DRect",
+	gl.IMAGE_3D:                                  "image3D",
+	gl.IMAGE_CUBE:                                "imageCube",
+	gl.IMAGE_CUBE_MAP_ARRAY:                      "imageCubeArray",
+	gl.IMAGE_BUFFER:                              "imageBuffer",
+	gl.INT_IMAGE_1D:                              "iimage1D",
+	gl.INT_IMAGE_1D_ARRAY:                        "iimage1DArray",
+	gl.INT_IMAGE_2D:                              "iimage2D",
+	gl.INT_IMAGE_2D_ARRAY:                        "iimage2DArray",
+	gl.INT_IMAGE_2D_MULTISAMPLE:                  "iimage2DMS",
+	gl.INT_IMAGE_2D_MULTISAMPLE_ARRAY:            "iimage2DMSArray",
+	gl.INT_IMAGE_2D_RECT:                         "iimage2DRect",
+	gl.INT_IMAGE_3D:                              "iimage3D",
+	gl.INT_IMAGE_CUBE:                            "iimageCube",
+	gl.INT_IMAGE_CUBE_MAP_ARRAY:                  "iimageCubeArray",
+	gl.INT_IMAGE_BUFFER:                          "iimageBuffer",
+	gl.UNSIGNED_INT_IMAGE_1D:                     "uimage1D",
+	gl.UNSIGNED_INT_IMAGE_1D_ARRAY:               "uimage1DArray",
+	gl.UNSIGNED_INT_IMAGE_2D:                     "uimage2D",
+	gl.UNSIGNED_INT_IMAGE_2D_ARRAY:               "uimage2DArray",
+	gl.UNSIGNED_INT_IMAGE_2D_MULTISAMPLE:         "uimage2DMS",
+	gl.UNSIGNED_INT_IMAGE_2D_MULTISAMPLE_ARRAY:   "uimage2DMSArray",
+	gl.UNSIGNED_INT_IMAGE_2D_RECT:                "uimage2DRect",
+	gl.UNSIGNED_INT_IMAGE_3D:                     "uimage3D",
+	gl.UNSIGNED_INT_IMAGE_CUBE:                   "uimageCube",
+	gl.UNSIGNED_INT_IMAGE_CUBE_MAP_ARRAY:         "uimageCubeArray",
+	gl.UNSIGNED_INT_IMAGE_BUFFER:                 "uimageBuffer",
+}
+
+// isSampler returns whether xtype is a sampler or image type, whose uniforms
+// are set to a texture unit or image unit with SetInt.
+func isSampler(xtype uint32) bool {
+	name := typeNames[xtype]
+	return strings.Contains(name, "sampler") || strings.Contains(name, "image")
+}
+
+// typeName returns the GLSL name of a type returned by reflection.
+func typeName(xtype uint32) string {
+	if name, ok := typeNames[xtype]; ok {
+		return name
+	}
+	return fmt.Sprintf("type 0x%x", xtype)
+}
@@ -0,0 +1,31 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+func TestIsSampler(t *testing.T) {
+	tests := []struct {
+		xtype uint32
+		want  bool
+	}{
+		{gl.SAMPLER_2D, true},
+		{gl.SAMPLER_2D_ARRAY_SHADOW, true},
+		{gl.SAMPLER_CUBE_MAP_ARRAY, true},
+		{gl.INT_SAMPLER_BUFFER, true},
+		{gl.UNSIGNED_INT_SAMPLER_2D_MULTISAMPLE, true},
+		{gl.IMAGE_2D, true},
+		{gl.UNSIGNED_INT_IMAGE_3D, true},
+		{gl.INT, false},
+		{gl.FLOAT_VEC4, false},
+		{0, false},
+	}
+
+	for _, tt := range tests {
+		if got := isSampler(tt.xtype); got != tt.want {
+			t.Errorf("isSampler(%s) = %v, want %v", typeName(tt.xtype), got, tt.want)
+		}
+	}
+}
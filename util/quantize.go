@@ -0,0 +1,123 @@
+package util
+
+import (
+	"image"
+	"image/color"
+	"sort"
+)
+
+// quantize picks a palette of at most n colors for img by median cut.
+// Colors are counted at 5 bits per channel, which is plenty to choose 256
+// colors from and keeps the histogram small.
+func quantize(img *image.NRGBA, n int) color.Palette {
+	counts := map[uint16]int{}
+	for i := 0; i < len(img.Pix); i += 4 {
+		counts[uint16(img.Pix[i]>>3)<<10|uint16(img.Pix[i+1]>>3)<<5|uint16(img.Pix[i+2]>>3)]++
+	}
+	var all []histColor
+	for c, count := range counts {
+		all = append(all, histColor{
+			rgb:   [3]uint8{expand5(c >> 10), expand5(c >> 5 & 31), expand5(c & 31)},
+			count: count,
+		})
+	}
+
+	boxes := []colorBox{{colors: all}}
+	for len(boxes) < n {
+		// Split the box with the widest range of any channel.
+		best, bestRange := -1, 0
+		for i, b := range boxes {
+			if len(b.colors) < 2 {
+				continue
+			}
+			if _, r := b.widest(); r > bestRange {
+				best, bestRange = i, r
+			}
+		}
+		if best < 0 {
+			break
+		}
+		a, b := boxes[best].split()
+		boxes[best] = a
+		boxes = append(boxes, b)
+	}
+
+	palette := make(color.Palette, 0, len(boxes))
+	for _, b := range boxes {
+		palette = append(palette, b.average())
+	}
+	return palette
+}
+
+// expand5 scales a 5 bit channel to 8 bits, so 31 becomes 255 rather than
+// 248.
+func expand5(v uint16) uint8 {
+	return uint8(v<<3 | v>>2)
+}
+
+// histColor is a color and how many pixels have it.
+type histColor struct {
+	rgb   [3]uint8
+	count int
+}
+
+// colorBox is a set of colors which will share a palette entry.
+type colorBox struct {
+	colors []histColor
+}
+
+// widest returns the channel with the largest range of values, and the
+// range.
+func (b colorBox) widest() (channel, width int) {
+	for c := 0; c < 3; c++ {
+		lo, hi := 255, 0
+		for _, hc := range b.colors {
+			v := int(hc.rgb[c])
+			if v < lo {
+				lo = v
+			}
+			if v > hi {
+				hi = v
+			}
+		}
+		if hi-lo > width {
+			channel, width = c, hi-lo
+		}
+	}
+	return channel, width
+}
+
+// split the box in two along its widest channel, at the median pixel.
+func (b colorBox) split() (colorBox, colorBox) {
+	c, _ := b.widest()
+	sort.Slice(b.colors, func(i, j int) bool { return b.colors[i].rgb[c] < b.colors[j].rgb[c] })
+	total := 0
+	for _, hc := range b.colors {
+		total += hc.count
+	}
+	i, sum := 0, 0
+	for ; i < len(b.colors)-1; i++ {
+		sum += b.colors[i].count
+		if sum >= total/2 {
+			i++
+			break
+		}
+	}
+	return colorBox{colors: b.colors[:i]}, colorBox{colors: b.colors[i:]}
+}
+
+// average returns the mean of the colors in the box, weighted by count.
+func (b colorBox) average() color.Color {
+	var sum [3]int
+	total := 0
+	for _, hc := range b.colors {
+		for c := 0; c < 3; c++ {
+			sum[c] += int(hc.rgb[c]) * hc.count
+		}
+		total += hc.count
+	}
+	if total == 0 {
+		return color.NRGBA{A: 255}
+	}
+	return color.NRGBA{uint8(sum[0] / total), uint8(sum[1] / total), uint8(sum[2] / total), 255}
+}
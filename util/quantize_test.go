@@ -0,0 +1,93 @@
+package util
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestQuantize(t *testing.T) {
+	// gradient has a different color in every pixel, far more than fit in
+	// a palette.
+	gradient := image.NewNRGBA(image.Rect(0, 0, 64, 64))
+	for y := 0; y < 64; y++ {
+		for x := 0; x < 64; x++ {
+			gradient.SetNRGBA(x, y, color.NRGBA{uint8(x * 4), uint8(y * 4), uint8(x + y), 255})
+		}
+	}
+	blackWhite := image.NewNRGBA(image.Rect(0, 0, 2, 1))
+	blackWhite.SetNRGBA(0, 0, color.NRGBA{0, 0, 0, 255})
+	blackWhite.SetNRGBA(1, 0, color.NRGBA{255, 255, 255, 255})
+
+	tests := []struct {
+		name string
+		img  *image.NRGBA
+		n    int
+		// want is the palette expected, if known.
+		want color.Palette
+	}{
+		{
+			name: "black and white",
+			img:  blackWhite,
+			n:    256,
+			want: color.Palette{color.NRGBA{0, 0, 0, 255}, color.NRGBA{255, 255, 255, 255}},
+		},
+		{name: "gradient to 256", img: gradient, n: 256},
+		{name: "gradient to 16", img: gradient, n: 16},
+		{name: "gradient to 1", img: gradient, n: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := quantize(tt.img, tt.n)
+			if len(got) == 0 || len(got) > tt.n {
+				t.Fatalf("palette has %d colors, want 1 to %d", len(got), tt.n)
+			}
+			if tt.want == nil {
+				return
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("palette = %v, want %v", got, tt.want)
+			}
+			for _, c := range tt.want {
+				if got[got.Index(c)] != c {
+					t.Errorf("palette %v does not contain %v", got, c)
+				}
+			}
+		})
+	}
+}
+
+func TestExpand5(t *testing.T) {
+	for v, want := range map[uint16]uint8{0: 0, 1: 8, 16: 132, 31: 255} {
+		if got := expand5(v); got != want {
+			t.Errorf("expand5(%d) = %d, want %d", v, got, want)
+		}
+	}
+}
+
+func TestColorBoxSplit(t *testing.T) {
+	// The green channel is widest, and half the pixels are the darkest
+	// green, so the split falls right after it.
+	b := colorBox{colors: []histColor{
+		{rgb: [3]uint8{10, 200, 0}, count: 1},
+		{rgb: [3]uint8{10, 0, 0}, count: 3},
+		{rgb: [3]uint8{20, 100, 0}, count: 1},
+		{rgb: [3]uint8{0, 150, 0}, count: 1},
+	}}
+	lo, hi := b.split()
+	if len(lo.colors) != 1 || lo.colors[0].rgb != [3]uint8{10, 0, 0} {
+		t.Errorf("low box = %v, want only {10 0 0}", lo.colors)
+	}
+	if len(hi.colors) != 3 {
+		t.Fatalf("high box = %v, want 3 colors", hi.colors)
+	}
+	for _, hc := range hi.colors {
+		if hc.rgb[1] < 100 {
+			t.Errorf("high box contains %v, below the split", hc.rgb)
+		}
+	}
+	if got, want := lo.average(), (color.NRGBA{10, 0, 0, 255}); got != want {
+		t.Errorf("low box average = %v, want %v", got, want)
+	}
+}
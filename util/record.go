@@ -0,0 +1,128 @@
+package util
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/gif"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// RecordOptions describe a clip for Run to record.  Time advances by 1/FPS
+// every frame however long the frames take to render, so recordings are the
+// same on any machine.
+type RecordOptions struct {
+	// Path to write to.  A path ending in .gif is written as an animated
+	// GIF, "-" streams raw RGBA frames to standard output, and anything else
+	// is a numbered PNG sequence.  The frame number replaces a %d verb in the
+	// path, or is added before the extension.
+	Path string
+	// Frames to record, 0 records until the window is closed.
+	Frames int
+	// FPS the clip plays back at, 30 if 0.
+	FPS float64
+}
+
+// recorder receives each frame of a recording.
+type recorder interface {
+	frame(img *image.NRGBA) error
+	close() error
+}
+
+// newRecorder returns the recorder for opts.Path, writing raw frames to
+// stdout.
+func newRecorder(opts RecordOptions, stdout io.Writer) recorder {
+	switch {
+	case opts.Path == "-":
+		return &rawRecorder{w: bufio.NewWriter(stdout), fps: opts.FPS}
+	case strings.EqualFold(filepath.Ext(opts.Path), ".gif"):
+		return &gifRecorder{path: opts.Path, delay: int(math.Round(100 / opts.FPS))}
+	default:
+		pattern := opts.Path
+		if !strings.Contains(pattern, "%") {
+			ext := filepath.Ext(pattern)
+			pattern = strings.TrimSuffix(pattern, ext) + "%04d" + ext
+		}
+		return &pngRecorder{pattern: pattern}
+	}
+}
+
+// pngRecorder writes each frame to its own PNG.
+type pngRecorder struct {
+	pattern string
+	n       int
+}
+
+func (r *pngRecorder) frame(img *image.NRGBA) error {
+	r.n++
+	if r.n == 1 {
+		if err := os.MkdirAll(filepath.Dir(r.pattern), 0755); err != nil {
+			return err
+		}
+	}
+	return writePNG(fmt.Sprintf(r.pattern, r.n), img)
+}
+
+func (r *pngRecorder) close() error {
+	logger.Printf("Recorded %d frames to %s\n", r.n, r.pattern)
+	return nil
+}
+
+// gifRecorder quantizes each frame to its own palette, and writes the GIF
+// once every frame has been recorded.
+type gifRecorder struct {
+	path string
+	// delay between frames in hundredths of a second, the unit GIF uses.
+	delay int
+	anim  gif.GIF
+}
+
+func (r *gifRecorder) frame(img *image.NRGBA) error {
+	paletted := image.NewPaletted(img.Bounds(), quantize(img, 256))
+	draw.FloydSteinberg.Draw(paletted, img.Bounds(), img, image.Point{})
+	r.anim.Image = append(r.anim.Image, paletted)
+	r.anim.Delay = append(r.anim.Delay, r.delay)
+	return nil
+}
+
+func (r *gifRecorder) close() error {
+	f, err := os.Create(r.path)
+	if err != nil {
+		return err
+	}
+	if err := gif.EncodeAll(f, &r.anim); err != nil {
+		f.Close()
+		return err
+	}
+	logger.Printf("Recorded %d frames to %s\n", len(r.anim.Image), r.path)
+	return f.Close()
+}
+
+// rawRecorder streams the RGBA bytes of each frame, for piping into an
+// encoder.
+type rawRecorder struct {
+	w   *bufio.Writer
+	fps float64
+	n   int
+}
+
+func (r *rawRecorder) frame(img *image.NRGBA) error {
+	if r.n == 0 {
+		size := img.Bounds().Size()
+		logger.Printf("Streaming %dx%d RGBA frames, for example:\n"+
+			"\t| ffmpeg -f rawvideo -pix_fmt rgba -s %dx%d -r %g -i - out.mp4\n",
+			size.X, size.Y, size.X, size.Y, r.fps)
+	}
+	r.n++
+	_, err := r.w.Write(img.Pix)
+	return err
+}
+
+func (r *rawRecorder) close() error {
+	return r.w.Flush()
+}
@@ -0,0 +1,47 @@
+package util
+
+import (
+	"io"
+	"testing"
+)
+
+func TestNewRecorder(t *testing.T) {
+	tests := []struct {
+		path string
+		// pattern is the file name pattern of a PNG sequence, or empty for
+		// other recorders.
+		pattern string
+		gif     bool
+		raw     bool
+	}{
+		{path: "frames/cube.png", pattern: "frames/cube%04d.png"},
+		{path: "cube", pattern: "cube%04d"},
+		{path: "frames/%d.png", pattern: "frames/%d.png"},
+		{path: "frames/cube-%03d.png", pattern: "frames/cube-%03d.png"},
+		{path: "cube.gif", gif: true},
+		{path: "cube.GIF", gif: true},
+		{path: "-", raw: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			switch r := newRecorder(RecordOptions{Path: tt.path, FPS: 30}, io.Discard).(type) {
+			case *pngRecorder:
+				if r.pattern != tt.pattern {
+					t.Errorf("pattern = %q, want %q", r.pattern, tt.pattern)
+				}
+			case *gifRecorder:
+				if !tt.gif {
+					t.Errorf("got a GIF recorder")
+				}
+				if r.delay != 3 {
+					t.Errorf("delay = %d, want 3", r.delay)
+				}
+			case *rawRecorder:
+				if !tt.raw {
+					t.Errorf("got a raw recorder")
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package util
+
+import (
+	"time"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// reloadInterval is how often Run checks the shader sources for changes.
+const reloadInterval = 250 * time.Millisecond
+
+// reloaders for the programs loaded with LoadReloadable, which Run checks for
+// changes between frames.
+var reloaders = map[*Program]*reloader{}
+
+// reloader rebuilds a program whenever one of its shader source files
+// changes.
+type reloader struct {
+	shaders   []ShaderInfo
+	separable bool
+
+	// modTimes of the shader sources the program was built from.
+	modTimes  map[string]time.Time
+	lastCheck time.Time
+}
+
+// LoadReloadable loads the shaders like Load, returning a program that is
+// rebuilt whenever the files change.  The new program replaces the old one
+// in place, so the returned *Program stays valid.  If the new sources fail to
+// compile or link, the compile log is printed and the last good program is
+// kept.  Embedded assets never change, so set Options.AssetDir to edit the
+// sources in place.
+func LoadReloadable(shaders []ShaderInfo) (*Program, error) {
+	return loadReloadable(shaders, false)
+}
+
+// LoadReloadableSeparable is the same as LoadReloadable, but the program is
+// loaded like LoadSeparable.
+func LoadReloadableSeparable(shaders []ShaderInfo) (*Program, error) {
+	return loadReloadable(shaders, true)
+}
+
+// loadReloadable the shaders
+func loadReloadable(shaders []ShaderInfo, separable bool) (*Program, error) {
+	r := &reloader{
+		shaders:   append([]ShaderInfo(nil), shaders...),
+		separable: separable,
+	}
+
+	p, err := load(&r.shaders, separable)
+	if err != nil {
+		return nil, err
+	}
+	r.modTimes = r.stat()
+	r.lastCheck = time.Now()
+
+	reloaders[p] = r
+	return p, nil
+}
+
+// Reload the program if it was loaded with LoadReloadable and any of its
+// shader sources have changed since it was last built, reporting whether the
+// program was replaced.  On failure the current program is left in place.
+func (p *Program) Reload() (bool, error) {
+	r, ok := reloaders[p]
+	if !ok {
+		return false, nil
+	}
+
+	modTimes := r.stat()
+	changed := len(modTimes) != len(r.modTimes)
+	for filename, t := range modTimes {
+		if !t.Equal(r.modTimes[filename]) {
+			changed = true
+		}
+	}
+	if !changed {
+		return false, nil
+	}
+	// Only try each version of the sources once, rather than every check
+	// until they are fixed.
+	r.modTimes = modTimes
+
+	next, err := load(&r.shaders, r.separable)
+	if err != nil {
+		return false, err
+	}
+	// The set of included files may have changed.
+	r.modTimes = r.stat()
+
+	var current int32
+	gl.GetIntegerv(gl.CURRENT_PROGRAM, &current)
+	if uint32(current) == p.ID {
+		gl.UseProgram(next.ID)
+	}
+	deleteProgram(p.ID)
+
+	onReload := p.OnReload
+	*p = *next
+	p.OnReload = onReload
+	attachUniformBuffers(p)
+	if p.OnReload != nil {
+		p.OnReload(p)
+	}
+
+	return true, nil
+}
+
+// stat returns the modification time of each shader source, including the
+// files they #include.  Files which cannot be read are left out, a reload
+// will report the error.
+func (r *reloader) stat() map[string]time.Time {
+	modTimes := map[string]time.Time{}
+	for _, s := range r.shaders {
+		files := []string{s.Filename}
+		for _, f := range s.sources {
+			files = append(files, f.name)
+		}
+		for _, filename := range files {
+			if fi, err := StatAsset(filename); err == nil {
+				modTimes[filename] = fi.ModTime()
+			}
+		}
+	}
+	return modTimes
+}
+
+// reloadPrograms checks every program loaded with LoadReloadable for
+// changes, at most once every reloadInterval.
+func reloadPrograms() {
+	for p, r := range reloaders {
+		if time.Since(r.lastCheck) < reloadInterval {
+			continue
+		}
+		r.lastCheck = time.Now()
+
+		if _, err := p.Reload(); err != nil {
+			logger.Println("Keeping last good program:", err)
+		}
+	}
+}
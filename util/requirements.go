@@ -0,0 +1,62 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Requirements an App has of the GL context.  Run checks them before Init,
+// and skips the App if the context falls short.
+type Requirements struct {
+	// Version of GL needed, 4.1 if zero.
+	Version GLVersion
+	// Extensions needed, such as ARB_compute_shader.  The GL_ prefix is
+	// optional.
+	Extensions []string
+}
+
+// version returns the version needed, defaulting to 4.1.
+func (r Requirements) version() GLVersion {
+	if r.Version == (GLVersion{}) {
+		return GLVersion{4, 1}
+	}
+	return r.Version
+}
+
+// String lists the requirements, such as "GL 4.3 / ARB_compute_shader".
+func (r Requirements) String() string {
+	s := []string{"GL " + r.version().String()}
+	for _, e := range r.Extensions {
+		s = append(s, strings.TrimPrefix(e, "GL_"))
+	}
+	return strings.Join(s, " / ")
+}
+
+// Check returns a *SkipError listing any requirements caps does not meet.
+func (r Requirements) Check(caps Capabilities) error {
+	var missing []string
+	if caps.Context.Less(r.version()) {
+		missing = append(missing, "GL "+r.version().String())
+	}
+	for _, e := range r.Extensions {
+		if !caps.HasExtension("GL_" + strings.TrimPrefix(e, "GL_")) {
+			missing = append(missing, strings.TrimPrefix(e, "GL_"))
+		}
+	}
+	if len(missing) > 0 {
+		return &SkipError{Missing: missing}
+	}
+	return nil
+}
+
+// SkipError is returned by Run when the context does not meet the App's
+// Requirements.  It is not a failure, the machine just cannot run the App.
+type SkipError struct {
+	// Missing requirements, such as "GL 4.3" or "ARB_compute_shader".
+	Missing []string
+}
+
+// Error formats the message as "skipped: requires GL 4.3 / ...".
+func (e *SkipError) Error() string {
+	return fmt.Sprintf("skipped: requires %s", strings.Join(e.Missing, " / "))
+}
@@ -0,0 +1,94 @@
+package util
+
+import "testing"
+
+func TestRequirementsCheck(t *testing.T) {
+	// caps is a 4.1 context; HasExtension needs the extensions sorted.
+	caps := Capabilities{
+		Context:    GLVersion{4, 1},
+		Extensions: []string{"GL_ARB_debug_output", "GL_KHR_debug"},
+	}
+
+	tests := []struct {
+		name string
+		req  Requirements
+		caps Capabilities
+		// want is the SkipError message, or empty if the check passes.
+		want string
+	}{
+		{
+			name: "default version",
+			caps: caps,
+		},
+		{
+			name: "older version",
+			req:  Requirements{Version: GLVersion{3, 3}},
+			caps: caps,
+		},
+		{
+			name: "newer minor version",
+			req:  Requirements{Version: GLVersion{4, 3}},
+			caps: caps,
+			want: "skipped: requires GL 4.3",
+		},
+		{
+			name: "newer major version",
+			req:  Requirements{Version: GLVersion{5, 0}},
+			caps: Capabilities{Context: GLVersion{4, 6}},
+			want: "skipped: requires GL 5.0",
+		},
+		{
+			name: "default version on an older context",
+			caps: Capabilities{Context: GLVersion{3, 3}},
+			want: "skipped: requires GL 4.1",
+		},
+		{
+			name: "extensions with and without prefix",
+			req:  Requirements{Extensions: []string{"KHR_debug", "GL_ARB_debug_output"}},
+			caps: caps,
+		},
+		{
+			name: "missing extension",
+			req:  Requirements{Extensions: []string{"ARB_compute_shader", "KHR_debug"}},
+			caps: caps,
+			want: "skipped: requires ARB_compute_shader",
+		},
+		{
+			name: "version and extension missing",
+			req: Requirements{
+				Version:    GLVersion{4, 3},
+				Extensions: []string{"GL_ARB_compute_shader"},
+			},
+			caps: caps,
+			want: "skipped: requires GL 4.3 / ARB_compute_shader",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.Check(tt.caps)
+			if tt.want == "" {
+				if err != nil {
+					t.Fatalf("Check() = %v, want nil", err)
+				}
+				return
+			}
+			if _, ok := err.(*SkipError); !ok {
+				t.Fatalf("Check() = %#v, want a *SkipError", err)
+			}
+			if err.Error() != tt.want {
+				t.Errorf("Check() = %q, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequirementsString(t *testing.T) {
+	r := Requirements{Version: GLVersion{4, 3}, Extensions: []string{"GL_ARB_compute_shader"}}
+	if got, want := r.String(), "GL 4.3 / ARB_compute_shader"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	if got, want := (Requirements{}).String(), "GL 4.1"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
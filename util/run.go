@@ -0,0 +1,342 @@
+package util
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"strconv"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/glfw/v3.1/glfw"
+)
+
+// App is implemented by each example.  Run drives an App through its
+// lifecycle so the examples only contain the code that differs between them.
+type App interface {
+	// Init is called once the GL context is current.  Shaders, buffers and
+	// VAOs should be created here.
+	Init() error
+	// Update advances the example by dt seconds.
+	Update(dt float64)
+	// Render draws a single frame.
+	Render()
+	// Resize is called with the framebuffer size before the first frame and
+	// any time it or the content scale changes, after the viewport has been
+	// set to cover it.
+	Resize(width, height int)
+	// Key is called for every event of a key with no BindKey binding.  Keys
+	// which are bound, including the Escape, H, F1, P, ., [, ] and F12 keys
+	// Run binds for every App, send their events to their bindings only, so
+	// an App binding a key should handle it there rather than in Key.
+	Key(key glfw.Key, action glfw.Action, mods glfw.ModifierKey)
+	// Close releases anything acquired in Init.  It is called even if Init
+	// fails, so it must cope with partially initialized state.
+	Close()
+}
+
+// FixedUpdater is implemented by an App which steps its simulation in fixed
+// size steps, so it behaves the same at any frame rate.  Run calls
+// FixedUpdate as many times as the clock says are due before each Update.
+type FixedUpdater interface {
+	FixedUpdate(step float64)
+}
+
+// Options used by Run to create the window.  Most options can also be set
+// with an environment variable: GORB_HEADLESS, GORB_FRAMES, GORB_TIMESTEP,
+// GORB_CAPTURE, GORB_CAPTURE_SCALE, GORB_ASSET_DIR, GORB_PROGRAM_CACHE, GORB_LEAKS and
+// GORB_DEBUG.
+type Options struct {
+	// Name displayed in the title bar.
+	Name string
+	// Width of the window.
+	Width int
+	// Height of the window.
+	Height int
+	// Window sets everything else about the window and its context.  Its
+	// Title, Width and Height default to Name, Width and Height.
+	Window WindowOptions
+	// Headless renders offscreen instead of opening a window.
+	Headless bool
+	// Frames to render before returning, 0 renders until the window is
+	// closed.  Headless runs render a single frame when this is 0.
+	Frames int
+	// Clock used to time frames, which the App can keep to pause or scale
+	// time.  Run creates one if nil.
+	Clock *Clock
+	// TimeStep in seconds the clock advances by every frame instead of the
+	// measured frame time, making runs reproducible.  0 uses real time.
+	TimeStep float64
+	// Capture is the filename of a PNG to save the last frame to, or the
+	// first frame if Frames is 0.  gorb run -screenshot sets it.
+	Capture string
+	// CaptureScale renders the captured frame at this many times the
+	// resolution and scales it down, for smoother edges.
+	CaptureScale int
+	// CaptureAlpha keeps the alpha channel of the captured frame.
+	CaptureAlpha bool
+	// Record a clip, see RecordOptions.  It overrides Frames and TimeStep.
+	// gorb run -record sets it.
+	Record *RecordOptions
+	// Assets the example reads its shaders from, usually an embed.FS.
+	Assets fs.FS
+	// AssetDir is a directory searched before Assets, so edits to the
+	// sources are picked up without rebuilding.
+	AssetDir string
+	// ProgramCache is a directory to cache linked program binaries in, see
+	// SetProgramCache.
+	ProgramCache string
+	// Leaks controls what happens to GL objects the App did not delete,
+	// see SetLeakMode.  GORB_LEAKS may be "report" or "fail".
+	Leaks LeakMode
+	// Debug creates a debug context and reports GL errors and warnings as
+	// they happen, see EnableDebugOutput.  GORB_DEBUG may be "1", or
+	// "panic" to panic on errors.
+	Debug *DebugOptions
+	// Requires is what the App needs of the context.  Run asks for at least
+	// that version, and returns a *SkipError without calling Init if the
+	// context it gets falls short.
+	Requires Requirements
+}
+
+// applyEnv overrides opts with the GORB_* environment variables that are set,
+// so a test harness can control an example without it knowing.
+func (o *Options) applyEnv() error {
+	if os.Getenv("GORB_HEADLESS") != "" {
+		o.Headless = true
+	}
+	if v := os.Getenv("GORB_FRAMES"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("invalid GORB_FRAMES: %s", err)
+		}
+		o.Frames = n
+	}
+	if v := os.Getenv("GORB_TIMESTEP"); v != "" {
+		dt, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return fmt.Errorf("invalid GORB_TIMESTEP: %s", err)
+		}
+		o.TimeStep = dt
+	}
+	if v := os.Getenv("GORB_CAPTURE"); v != "" {
+		o.Capture = v
+	}
+	if v := os.Getenv("GORB_CAPTURE_SCALE"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("invalid GORB_CAPTURE_SCALE: %s", err)
+		}
+		o.CaptureScale = n
+	}
+	if v := os.Getenv("GORB_ASSET_DIR"); v != "" {
+		o.AssetDir = v
+	}
+	if v := os.Getenv("GORB_PROGRAM_CACHE"); v != "" {
+		o.ProgramCache = v
+	}
+	switch v := os.Getenv("GORB_LEAKS"); v {
+	case "":
+	case "report":
+		o.Leaks = LeaksReport
+	case "fail":
+		o.Leaks = LeaksFail
+	default:
+		return fmt.Errorf("invalid GORB_LEAKS: %q is not report or fail", v)
+	}
+	if v := os.Getenv("GORB_DEBUG"); v != "" {
+		var d DebugOptions
+		if o.Debug != nil {
+			d = *o.Debug
+		}
+		d.Panic = d.Panic || v == "panic"
+		o.Debug = &d
+	}
+	return nil
+}
+
+// Run creates a window, initializes app and runs the main loop until the
+// window is closed.  Any programs, buffers or VAOs created through this
+// package which app did not delete itself are deleted before Run returns,
+// and reported as leaks if Options.Leaks asks for it.
+func Run(app App, opts Options) (err error) {
+	if err := opts.applyEnv(); err != nil {
+		return err
+	}
+
+	var rec recorder
+	if opts.Record != nil {
+		r := *opts.Record
+		if r.FPS <= 0 {
+			r.FPS = 30
+		}
+		opts.Frames = r.Frames
+		opts.TimeStep = 1 / r.FPS
+		// Raw frames go to standard output, which util leaves alone by
+		// logging to standard error.
+		rec = newRecorder(r, os.Stdout)
+	}
+	SetAssets(AssetFS(opts.Assets, opts.AssetDir))
+	SetProgramCache(opts.ProgramCache)
+	SetLeakMode(opts.Leaks)
+	leaked = nil
+	defer func() {
+		reportLeaks()
+		if n := len(Leaks()); err == nil && opts.Leaks == LeaksFail && n > 0 {
+			err = fmt.Errorf("%d GL objects leaked", n)
+		}
+	}()
+
+	SetDebugContext(opts.Debug != nil)
+	defer resetKeys()
+
+	wopts := opts.Window
+	if wopts.Title == "" {
+		wopts.Title = opts.Name
+	}
+	if wopts.Width == 0 && wopts.Height == 0 {
+		wopts.Width, wopts.Height = opts.Width, opts.Height
+	}
+	if wopts.versions()[0].Less(opts.Requires.version()) {
+		wopts.Version = opts.Requires.version()
+	}
+
+	var window Window
+	var scale float32 = 1
+	headless := opts.Headless
+	if headless {
+		h, err := NewHeadless(wopts)
+		if err != nil {
+			return err
+		}
+		defer h.Destroy()
+		window = h
+
+		if opts.Frames == 0 {
+			opts.Frames = 1
+		}
+	} else {
+		w, err := NewWindow(wopts)
+		if err != nil {
+			return err
+		}
+		defer Terminate()
+		window = w
+		currentWindow = w
+		defer func() { currentWindow = nil }()
+
+		w.SetKeyCallback(func(w *glfw.Window, key glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey) {
+			if !handleKey(key, action) {
+				app.Key(key, action, mods)
+			}
+			pollCallbackErrors("Key")
+		})
+		resize := func(w *glfw.Window) {
+			width, height := w.GetFramebufferSize()
+			// Minimized windows have no framebuffer, keep the old size.
+			if width == 0 || height == 0 {
+				return
+			}
+			if setFramebufferSize(width, height, windowScale(w)) {
+				app.Resize(width, height)
+				pollCallbackErrors("Resize")
+			}
+		}
+		w.SetFramebufferSizeCallback(func(w *glfw.Window, width, height int) { resize(w) })
+		// Moving between displays can change the scale without changing the
+		// framebuffer size.
+		w.SetSizeCallback(func(w *glfw.Window, width, height int) { resize(w) })
+		scale = windowScale(w)
+	}
+	if err := opts.Requires.Check(QueryCapabilities()); err != nil {
+		return err
+	}
+	if opts.Debug != nil {
+		EnableDebugOutput(*opts.Debug)
+	}
+	defer deleteObjects()
+
+	width, height := window.GetFramebufferSize()
+	setFramebufferSize(width, height, scale)
+
+	defer app.Close()
+	if err := app.Init(); err != nil {
+		return err
+	}
+	pollErrors("Init")
+	app.Resize(width, height)
+	pollErrors("Resize")
+
+	clock := opts.Clock
+	if clock == nil {
+		clock = NewClock(RealTime())
+	}
+	if opts.TimeStep > 0 {
+		clock.Source = FixedTime(opts.TimeStep)
+	}
+	screenshot := false
+	if !headless {
+		bindClockKeys(clock)
+		BindKey("screenshot", glfw.KeyF12, "Save a screenshot to the next free screenshotN.png", func() {
+			screenshot = true
+		})
+	}
+	fixed, _ := app.(FixedUpdater)
+
+	for frame := 0; !window.ShouldClose(); frame++ {
+		reloadPrograms()
+
+		dt := clock.Tick()
+		if fixed != nil {
+			for n := clock.Steps(); n > 0; n-- {
+				fixed.FixedUpdate(clock.FixedStep)
+			}
+		}
+		app.Update(dt)
+		pollErrors("Update")
+
+		app.Render()
+		pollErrors("Render")
+
+		lastFrame := opts.Frames > 0 && frame+1 >= opts.Frames
+		if opts.Capture != "" && (lastFrame || opts.Frames == 0 && frame == 0) {
+			if err := capture(app, opts.Capture, opts.CaptureScale, opts.CaptureAlpha); err != nil {
+				return fmt.Errorf("failed to capture frame: %s", err)
+			}
+		}
+		if rec != nil {
+			width, height := FramebufferSize()
+			if err := rec.frame(readFramebuffer(0, width, height, false)); err != nil {
+				return fmt.Errorf("failed to record frame: %s", err)
+			}
+		}
+		if screenshot {
+			screenshot = false
+			filename := nextScreenshotFile()
+			if err := capture(app, filename, opts.CaptureScale, opts.CaptureAlpha); err != nil {
+				logger.Printf("Failed to save screenshot: %s\n", err)
+			} else {
+				logger.Println("Saved", filename)
+			}
+		}
+
+		// Swap Buffers
+		gl.Flush()
+		window.SwapBuffers()
+		if !headless {
+			glfw.PollEvents()
+			panicPending()
+		}
+
+		if lastFrame {
+			window.SetShouldClose(true)
+		}
+	}
+
+	if rec != nil {
+		if err := rec.close(); err != nil {
+			return fmt.Errorf("failed to write recording: %s", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,186 @@
+package util
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"os"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// nextScreenshotFile returns the first of screenshot1.png, screenshot2.png
+// and so on which does not exist in the working directory, so the
+// screenshot key never overwrites a file.
+func nextScreenshotFile() string {
+	for n := 1; ; n++ {
+		name := fmt.Sprintf("screenshot%d.png", n)
+		if _, err := os.Stat(name); err != nil {
+			return name
+		}
+	}
+}
+
+// ScreenshotOptions control what Screenshot reads.
+type ScreenshotOptions struct {
+	// Framebuffer to read, 0 for the one currently being drawn to.
+	Framebuffer uint32
+	// Width and Height to read, FramebufferSize if 0.
+	Width, Height int
+	// Alpha keeps the alpha channel, otherwise the image is opaque.  The
+	// alpha of a window is rarely meaningful.
+	Alpha bool
+}
+
+// Screenshot reads a framebuffer and writes it to filename as a PNG.
+func Screenshot(filename string, opts ScreenshotOptions) error {
+	width, height := opts.Width, opts.Height
+	if width == 0 || height == 0 {
+		width, height = FramebufferSize()
+	}
+	return writePNG(filename, readFramebuffer(opts.Framebuffer, width, height, opts.Alpha))
+}
+
+// capture saves the frame app has just rendered to filename, rendering it
+// again at scale times the resolution if scale is more than 1.
+func capture(app App, filename string, scale int, alpha bool) error {
+	if scale <= 1 {
+		return Screenshot(filename, ScreenshotOptions{Alpha: alpha})
+	}
+	img, err := supersample(app, scale, alpha)
+	if err != nil {
+		return err
+	}
+	return writePNG(filename, img)
+}
+
+// readFramebuffer reads the color of fbo, or the framebuffer being drawn to
+// if it is 0.
+func readFramebuffer(fbo uint32, width, height int, alpha bool) *image.NRGBA {
+	var prev int32
+	gl.GetIntegerv(gl.READ_FRAMEBUFFER_BINDING, &prev)
+	if fbo == 0 {
+		var draw int32
+		gl.GetIntegerv(gl.DRAW_FRAMEBUFFER_BINDING, &draw)
+		fbo = uint32(draw)
+	}
+	gl.BindFramebuffer(gl.READ_FRAMEBUFFER, fbo)
+
+	// ReadPixels returns the stored values unchanged, so an sRGB framebuffer
+	// reads back already encoded, which is what a PNG holds.
+	img := readPixels(0, 0, width, height)
+	gl.BindFramebuffer(gl.READ_FRAMEBUFFER, uint32(prev))
+
+	if !alpha {
+		for i := 3; i < len(img.Pix); i += 4 {
+			img.Pix[i] = 255
+		}
+	}
+	return img
+}
+
+// supersample renders a frame of app into a framebuffer scale times the
+// size of the current one, and scales it back down.
+func supersample(app App, scale int, alpha bool) (*image.NRGBA, error) {
+	width, height := FramebufferSize()
+	contentScale := ContentScale()
+	w, h := width*scale, height*scale
+
+	var max int32
+	gl.GetIntegerv(gl.MAX_RENDERBUFFER_SIZE, &max)
+	if w > int(max) || h > int(max) {
+		return nil, fmt.Errorf("%dx%d is larger than the largest renderbuffer, %d", w, h, max)
+	}
+
+	var prev int32
+	gl.GetIntegerv(gl.DRAW_FRAMEBUFFER_BINDING, &prev)
+	format := uint32(gl.RGBA8)
+	if gl.IsEnabled(gl.FRAMEBUFFER_SRGB) {
+		format = gl.SRGB8_ALPHA8
+	}
+
+	var fbo uint32
+	var renderbuffers [2]uint32
+	gl.GenFramebuffers(1, &fbo)
+	gl.BindFramebuffer(gl.FRAMEBUFFER, fbo)
+	gl.GenRenderbuffers(2, &renderbuffers[0])
+	gl.BindRenderbuffer(gl.RENDERBUFFER, renderbuffers[0])
+	gl.RenderbufferStorage(gl.RENDERBUFFER, format, int32(w), int32(h))
+	gl.FramebufferRenderbuffer(gl.FRAMEBUFFER, gl.COLOR_ATTACHMENT0, gl.RENDERBUFFER, renderbuffers[0])
+	gl.BindRenderbuffer(gl.RENDERBUFFER, renderbuffers[1])
+	gl.RenderbufferStorage(gl.RENDERBUFFER, gl.DEPTH24_STENCIL8, int32(w), int32(h))
+	gl.FramebufferRenderbuffer(gl.FRAMEBUFFER, gl.DEPTH_STENCIL_ATTACHMENT, gl.RENDERBUFFER, renderbuffers[1])
+	gl.BindRenderbuffer(gl.RENDERBUFFER, 0)
+	defer func() {
+		gl.BindFramebuffer(gl.FRAMEBUFFER, uint32(prev))
+		gl.DeleteRenderbuffers(2, &renderbuffers[0])
+		gl.DeleteFramebuffers(1, &fbo)
+		setFramebufferSize(width, height, contentScale)
+		app.Resize(width, height)
+	}()
+
+	if status := gl.CheckFramebufferStatus(gl.FRAMEBUFFER); status != gl.FRAMEBUFFER_COMPLETE {
+		return nil, fmt.Errorf("screenshot framebuffer incomplete: 0x%x", status)
+	}
+
+	setFramebufferSize(w, h, contentScale*float32(scale))
+	app.Resize(w, h)
+	app.Render()
+	return downsample(readFramebuffer(fbo, w, h, alpha), scale), nil
+}
+
+// downsample averages each scale by scale block of img into one pixel.  The
+// pixels are averaged in linear light and weighted by their alpha, so edges
+// are not darkened.
+func downsample(img *image.NRGBA, scale int) *image.NRGBA {
+	b := img.Bounds()
+	out := image.NewNRGBA(image.Rect(0, 0, b.Dx()/scale, b.Dy()/scale))
+	n := float64(scale * scale)
+	for y := 0; y < out.Rect.Dy(); y++ {
+		for x := 0; x < out.Rect.Dx(); x++ {
+			var r, g, bl, a float64
+			for sy := 0; sy < scale; sy++ {
+				i := img.PixOffset(x*scale, y*scale+sy)
+				for sx := 0; sx < scale; sx, i = sx+1, i+4 {
+					pa := float64(img.Pix[i+3]) / 255
+					r += srgbToLinear[img.Pix[i]] * pa
+					g += srgbToLinear[img.Pix[i+1]] * pa
+					bl += srgbToLinear[img.Pix[i+2]] * pa
+					a += pa
+				}
+			}
+			o := out.PixOffset(x, y)
+			if a > 0 {
+				out.Pix[o] = linearToSRGB(r / a)
+				out.Pix[o+1] = linearToSRGB(g / a)
+				out.Pix[o+2] = linearToSRGB(bl / a)
+			}
+			out.Pix[o+3] = uint8(math.Round(a / n * 255))
+		}
+	}
+	return out
+}
+
+// srgbToLinear decodes each 8 bit sRGB value.
+var srgbToLinear [256]float64
+
+func init() {
+	for i := range srgbToLinear {
+		c := float64(i) / 255
+		if c <= 0.04045 {
+			srgbToLinear[i] = c / 12.92
+		} else {
+			srgbToLinear[i] = math.Pow((c+0.055)/1.055, 2.4)
+		}
+	}
+}
+
+// linearToSRGB encodes a linear value from 0 to 1 as 8 bit sRGB.
+func linearToSRGB(c float64) uint8 {
+	if c <= 0.0031308 {
+		c *= 12.92
+	} else {
+		c = 1.055*math.Pow(c, 1/2.4) - 0.055
+	}
+	return uint8(math.Round(math.Max(0, math.Min(1, c)) * 255))
+}
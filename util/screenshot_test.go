@@ -0,0 +1,26 @@
+package util
+
+import (
+	"os"
+	"testing"
+)
+
+func TestNextScreenshotFile(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	for _, name := range []string{"screenshot.png", "screenshot1.png", "screenshot2.png", "screenshot4.png"} {
+		if err := os.WriteFile(name, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := nextScreenshotFile(); got != "screenshot3.png" {
+		t.Errorf("nextScreenshotFile() = %s, want screenshot3.png", got)
+	}
+}
@@ -1,9 +1,7 @@
 package util
 
 import (
-	"bytes"
 	"fmt"
-	"os"
 	"runtime"
 	"strings"
 
@@ -17,41 +15,76 @@ func init() {
 
 // ShaderInfo representing a shader.
 type ShaderInfo struct {
-	// Type of shader: gl.VERTEX_SHADER, gl.FRAGMENT_SHADER, ...
+	// Type of shader: gl.VERTEX_SHADER, gl.TESS_CONTROL_SHADER,
+	// gl.TESS_EVALUATION_SHADER, gl.GEOMETRY_SHADER or gl.FRAGMENT_SHADER.
 	Type uint32
-	// Filename of shader source file.
+	// Filename of shader source file, within the assets given to Run.
 	Filename string
+	// Defines injected after the #version line, as "#define name value".
+	Defines map[string]string
+	// IncludePaths searched for #include <name>.  #include "name" looks next
+	// to the including file first.
+	IncludePaths []string
+	// Warnings reported by the last Compile, which are kept even when it
+	// succeeds.
+	Warnings []Diagnostic
 	// shader ID.
 	shader uint32
+	// source after preprocessing.
+	source string
+	// sources the shader was built from, indexed by source string number.
+	sources []sourceFile
 }
 
-// Load the shaders, returning the ID of the resulting program.  Any problems
+// Load the shaders, returning the resulting program.  Any problems
 // compiling or linking will result in an error.
-func Load(shaders *[]ShaderInfo) (uint32, error) {
+func Load(shaders *[]ShaderInfo) (*Program, error) {
 	return load(shaders, false)
 }
 
 // LoadSeparable is the same as Load with the exception that before the link stage
 // GL_PROGRAM_SEPARABLE is set to GL_TRUE.
-func LoadSeparable(shaders *[]ShaderInfo) (uint32, error) {
+func LoadSeparable(shaders *[]ShaderInfo) (*Program, error) {
 	return load(shaders, true)
 }
 
-// load the shaders
-func load(shaders *[]ShaderInfo, separable bool) (uint32, error) {
+// load the shaders, from the program cache if possible.
+func load(shaders *[]ShaderInfo, separable bool) (*Program, error) {
+	var stages uint32
+	for i := range *shaders {
+		stage, err := stageBit((*shaders)[i].Type)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", (*shaders)[i].Filename, err)
+		}
+		stages |= stage
+
+		if err := (*shaders)[i].preprocess(); err != nil {
+			return nil, err
+		}
+	}
+
+	key := cacheKey(*shaders, separable)
+	if program, ok := loadCached(key, separable); ok {
+		objects.programs.add(program)
+		return newProgram(program, stages, separable), nil
+	}
+
 	program := gl.CreateProgram()
 
-	for _, s := range *shaders {
-		if err := s.Compile(program); err != nil {
+	for i := range *shaders {
+		if err := (*shaders)[i].compile(program); err != nil {
 			cleanup(shaders)
 			gl.DeleteProgram(program)
-			return 0, err
+			return nil, err
 		}
 	}
 
 	if separable {
 		gl.ProgramParameteri(program, gl.PROGRAM_SEPARABLE, gl.TRUE)
 	}
+	if key != "" {
+		gl.ProgramParameteri(program, gl.PROGRAM_BINARY_RETRIEVABLE_HINT, gl.TRUE)
+	}
 
 	gl.LinkProgram(program)
 	cleanup(shaders)
@@ -59,71 +92,108 @@ func load(shaders *[]ShaderInfo, separable bool) (uint32, error) {
 	if gl.GetProgramiv(program, gl.LINK_STATUS, &linked); linked == gl.FALSE {
 		msg := getErrorMsg(false, program)
 		gl.DeleteProgram(program)
-		return 0, fmt.Errorf("failed to link program: %s", msg)
+		return nil, &ShaderError{Diagnostics: parseLog(msg, nil), Log: msg}
+	}
+	warnings := parseLog(getErrorMsg(false, program), nil)
+	for _, d := range warnings {
+		logger.Println(d)
 	}
+	if err := saveCached(key, program); err != nil {
+		logger.Println("Warning: failed to cache program:", err)
+	}
+
+	objects.programs.add(program)
+	p := newProgram(program, stages, separable)
+	p.Warnings = warnings
+	return p, nil
+}
+
+// stageBits for each type of shader, as used by glUseProgramStages.
+var stageBits = map[uint32]uint32{
+	gl.VERTEX_SHADER:          gl.VERTEX_SHADER_BIT,
+	gl.TESS_CONTROL_SHADER:    gl.TESS_CONTROL_SHADER_BIT,
+	gl.TESS_EVALUATION_SHADER: gl.TESS_EVALUATION_SHADER_BIT,
+	gl.GEOMETRY_SHADER:        gl.GEOMETRY_SHADER_BIT,
+	gl.FRAGMENT_SHADER:        gl.FRAGMENT_SHADER_BIT,
+}
 
-	return program, nil
+// stageBit returns the stage bit for a type of shader.  GL 4.1 has no
+// compute shaders, so those are rejected along with anything unknown.
+func stageBit(shaderType uint32) (uint32, error) {
+	stage, ok := stageBits[shaderType]
+	if !ok {
+		return 0, fmt.Errorf("unsupported shader type 0x%x", shaderType)
+	}
+	return stage, nil
 }
 
 // Compile the shader using the info provided.
 func (i *ShaderInfo) Compile(program uint32) error {
-	i.shader = gl.CreateShader(i.Type)
-	if i.shader == 0 {
-		return fmt.Errorf("could not create shader")
+	if err := i.preprocess(); err != nil {
+		return err
 	}
+	return i.compile(program)
+}
 
-	source, err := readShader(i.Filename)
+// preprocess the source file, expanding includes and defines.
+func (i *ShaderInfo) preprocess() error {
+	source, sources, err := preprocess(assets, i.Filename, i.Defines, i.IncludePaths)
 	if err != nil {
 		return err
 	}
+	i.source = source
+	i.sources = sources
+	return nil
+}
 
-	csrc, free := gl.Strs(source)
+// compile the preprocessed source and attach it to program.
+func (i *ShaderInfo) compile(program uint32) error {
+	i.shader = gl.CreateShader(i.Type)
+	if i.shader == 0 {
+		return fmt.Errorf("could not create shader")
+	}
+	objects.shaders.add(i.shader)
+
+	csrc, free := gl.Strs(i.source + "\x00")
 	gl.ShaderSource(i.shader, 1, csrc, nil)
 	free()
 	gl.CompileShader(i.shader)
 
+	msg := getErrorMsg(true, i.shader)
 	var compiled int32
 	if gl.GetShaderiv(i.shader, gl.COMPILE_STATUS, &compiled); compiled == gl.FALSE {
-		return fmt.Errorf("failed to compile %s: %s", i.Filename, getErrorMsg(true, i.shader))
+		return &ShaderError{Filename: i.Filename, Diagnostics: parseLog(msg, i.sources), Log: msg}
+	}
+	i.Warnings = parseLog(msg, i.sources)
+	for _, d := range i.Warnings {
+		logger.Println(d)
 	}
 	gl.AttachShader(program, i.shader)
 	return nil
 }
 
-func readShader(filename string) (string, error) {
-	f, err := os.Open(filename)
-	defer f.Close()
-	if err != nil {
-		return "", err
-	}
-
-	buf := new(bytes.Buffer)
-	buf.ReadFrom(f)
-	source := fmt.Sprintf("%s\x00", buf.String())
-
-	return source, nil
-}
-
 // Delete the shader
 func (i *ShaderInfo) Delete() {
+	objects.shaders.remove(i.shader)
 	gl.DeleteShader(i.shader)
+	i.shader = 0
 }
 
 // cleanup all shaders by calling Delete on any non-zero shader in the slice.
 func cleanup(shaders *[]ShaderInfo) {
-	for _, s := range *shaders {
-		if s.shader != 0 {
-			s.Delete()
+	for i := range *shaders {
+		if (*shaders)[i].shader != 0 {
+			(*shaders)[i].Delete()
 		}
 	}
 }
 
-// getErrorsMsg helps to return an error message when compiling/linking goes
-// wrong.  If shader is true, check for logs relating to a shader failing to
-// compile.  In this context id should be the ID of the shader that failed to
-// compile.  If shader is false, check for logs relating to linking shaters
-// to a program.  in this context, id is the ID of the program that was
-// attempting to link the shaders.
+// getErrorMsg helps to return an error message when compiling/linking goes
+// wrong, or any warnings when it succeeds.  If shader is true, check for logs
+// relating to a shader failing to compile.  In this context id should be the
+// ID of the shader that failed to compile.  If shader is false, check for logs
+// relating to linking shaders to a program.  In this context, id is the ID of
+// the program that was attempting to link the shaders.
 func getErrorMsg(shader bool, id uint32) string {
 	var l int32
 	if shader {
@@ -140,5 +210,5 @@ func getErrorMsg(shader bool, id uint32) string {
 		gl.GetProgramInfoLog(id, l, nil, gl.Str(msg))
 	}
 
-	return msg
+	return strings.TrimRight(msg, "\x00")
 }
@@ -0,0 +1,383 @@
+package util
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Layout of the data in a block.
+type Layout int
+
+// Layouts a block can be declared with.  Std430 packs arrays and structs
+// more tightly, but GL 4.1 only allows std140 for uniform blocks.
+const (
+	Std140 Layout = iota
+	Std430
+)
+
+// String returns the layout qualifier.
+func (l Layout) String() string {
+	if l == Std430 {
+		return "std430"
+	}
+	return "std140"
+}
+
+// uniformBuffers by the name of the block they hold.
+var uniformBuffers = map[string]*UniformBuffer{}
+
+// UniformBuffer is a uniform buffer object holding a Go struct, laid out to
+// match a uniform block of the same name.  Each buffer has its own binding
+// point, and programs using the block are attached to it with Attach.
+//
+// Struct fields map to block members by name, which can be changed with a
+// glsl tag, e.g. `glsl:"modelMatrix"`.  Fields may be float32, int32,
+// uint32, bool, mgl32 vectors and matrices, structs, and arrays of these.
+type UniformBuffer struct {
+	// ID of the GL buffer.
+	ID uint32
+	// Name of the uniform block.
+	Name string
+	// Binding point the buffer is bound to.
+	Binding uint32
+	Layout  Layout
+
+	// typ of the struct held in the buffer.
+	typ reflect.Type
+	// fields of the struct, with their offsets.
+	fields []blockField
+	// data is the struct encoded in the layout.
+	data []byte
+}
+
+// NewUniformBuffer creates a buffer for the block name, laid out from v,
+// which must be a struct or a pointer to one, and uploads v.
+func NewUniformBuffer(name string, layout Layout, v interface{}) (*UniformBuffer, error) {
+	if _, ok := uniformBuffers[name]; ok {
+		return nil, fmt.Errorf("uniform buffer for block %s already exists", name)
+	}
+
+	t := reflect.TypeOf(v)
+	if t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("uniform block %s must be a struct, not %v", name, t)
+	}
+	u := &UniformBuffer{
+		Name:    name,
+		Binding: nextBinding(),
+		Layout:  layout,
+		typ:     t,
+	}
+	_, size, err := layout.structLayout(t, "", 0, &u.fields)
+	if err != nil {
+		return nil, fmt.Errorf("uniform block %s: %s", name, err)
+	}
+	u.data = make([]byte, size)
+
+	GenBuffers(1, &u.ID)
+	gl.BindBuffer(gl.UNIFORM_BUFFER, u.ID)
+	gl.BufferData(gl.UNIFORM_BUFFER, size, nil, gl.DYNAMIC_DRAW)
+	gl.BindBufferBase(gl.UNIFORM_BUFFER, u.Binding, u.ID)
+	uniformBuffers[name] = u
+
+	if err := u.Set(v); err != nil {
+		u.Delete()
+		return nil, err
+	}
+	return u, nil
+}
+
+// nextBinding returns the lowest binding point not used by a buffer.
+func nextBinding() uint32 {
+	used := map[uint32]bool{}
+	for _, u := range uniformBuffers {
+		used[u.Binding] = true
+	}
+	var binding uint32
+	for used[binding] {
+		binding++
+	}
+	return binding
+}
+
+// Set uploads v, which must be of the type the buffer was created with.
+func (u *UniformBuffer) Set(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr {
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() || rv.Type() != u.typ {
+		return fmt.Errorf("uniform block %s holds a %s, not a %T", u.Name, u.typ, v)
+	}
+	u.Layout.encode(rv, u.data, 0)
+	gl.BindBuffer(gl.UNIFORM_BUFFER, u.ID)
+	gl.BufferSubData(gl.UNIFORM_BUFFER, 0, len(u.data), gl.Ptr(u.data))
+	return nil
+}
+
+// Attach p's block of the same name to the buffer, after checking the
+// layout GL chose for the block matches the struct.
+func (u *UniformBuffer) Attach(p *Program) error {
+	b, ok := p.UniformBlocks[u.Name]
+	if !ok {
+		return fmt.Errorf("program %d has no active uniform block %s", p.ID, u.Name)
+	}
+	if err := u.check(b); err != nil {
+		return err
+	}
+	gl.UniformBlockBinding(p.ID, b.Index, u.Binding)
+	b.Binding = u.Binding
+	p.UniformBlocks[u.Name] = b
+	return nil
+}
+
+// check the reflected layout of b against the struct.
+func (u *UniformBuffer) check(b UniformBlock) error {
+	if int(b.DataSize) > len(u.data) {
+		return fmt.Errorf("uniform block %s needs %d bytes, the %s layout of %s has %d", u.Name, b.DataSize, u.Layout, u.typ, len(u.data))
+	}
+	for _, m := range b.Uniforms {
+		// Members of a block with an instance name are prefixed with the
+		// block name.
+		name := strings.TrimPrefix(m.Name, b.Name+".")
+		var f *blockField
+		for i := range u.fields {
+			if u.fields[i].name == name {
+				f = &u.fields[i]
+			}
+		}
+		switch {
+		case f == nil:
+			return fmt.Errorf("uniform block %s: %s has no field for %s", u.Name, u.typ, name)
+		case f.glType != m.Type:
+			return fmt.Errorf("uniform block %s: %s is a %s, the field is a %s", u.Name, name, typeName(m.Type), typeName(f.glType))
+		case f.offset != int(m.Offset):
+			return fmt.Errorf("uniform block %s: %s is at offset %d, the %s layout puts it at %d", u.Name, name, m.Offset, u.Layout, f.offset)
+		case m.Size > int32(f.size):
+			return fmt.Errorf("uniform block %s: %s has %d elements, the field has %d", u.Name, name, m.Size, f.size)
+		case m.ArrayStride > 0 && int(m.ArrayStride) != f.arrayStride:
+			return fmt.Errorf("uniform block %s: %s has an array stride of %d, the %s layout has %d", u.Name, name, m.ArrayStride, u.Layout, f.arrayStride)
+		case m.MatrixStride > 0 && int(m.MatrixStride) != f.matrixStride:
+			return fmt.Errorf("uniform block %s: %s has a matrix stride of %d, the %s layout has %d", u.Name, name, m.MatrixStride, u.Layout, f.matrixStride)
+		}
+	}
+	return nil
+}
+
+// Delete the buffer, freeing its binding point.
+func (u *UniformBuffer) Delete() {
+	delete(uniformBuffers, u.Name)
+	DeleteBuffers(1, &u.ID)
+	u.ID = 0
+}
+
+// attachUniformBuffers attaches p's blocks to any buffer of the same name.
+// Block bindings do not survive a relink, so this is done after a reload.
+func attachUniformBuffers(p *Program) {
+	for name := range p.UniformBlocks {
+		if u, ok := uniformBuffers[name]; ok {
+			if err := u.Attach(p); err != nil {
+				logger.Println("Warning:", err)
+			}
+		}
+	}
+}
+
+// blockField is a scalar, vector or matrix member of a block, or an array of
+// them, named as GL reports it, e.g. "lights[1].color" or "weights[0]".
+type blockField struct {
+	name   string
+	glType uint32
+	offset int
+	// size is the length of the array, 1 if it is not an array.
+	size         int
+	arrayStride  int
+	matrixStride int
+}
+
+// vector and matrix types, with their GL type, rows and columns.
+var blockTypes = map[reflect.Type]struct {
+	glType        uint32
+	rows, columns int
+}{
+	reflect.TypeOf(float32(0)):   {gl.FLOAT, 1, 1},
+	reflect.TypeOf(int32(0)):     {gl.INT, 1, 1},
+	reflect.TypeOf(uint32(0)):    {gl.UNSIGNED_INT, 1, 1},
+	reflect.TypeOf(false):        {gl.BOOL, 1, 1},
+	reflect.TypeOf(mgl32.Vec2{}): {gl.FLOAT_VEC2, 2, 1},
+	reflect.TypeOf(mgl32.Vec3{}): {gl.FLOAT_VEC3, 3, 1},
+	reflect.TypeOf(mgl32.Vec4{}): {gl.FLOAT_VEC4, 4, 1},
+	reflect.TypeOf(mgl32.Mat2{}): {gl.FLOAT_MAT2, 2, 2},
+	reflect.TypeOf(mgl32.Mat3{}): {gl.FLOAT_MAT3, 3, 3},
+	reflect.TypeOf(mgl32.Mat4{}): {gl.FLOAT_MAT4, 4, 4},
+}
+
+// vectorAlign returns the base alignment of a vector with n components.
+func vectorAlign(n int) int {
+	if n == 1 || n == 2 {
+		return 4 * n
+	}
+	return 16
+}
+
+// roundUp n to a multiple of align.
+func roundUp(n, align int) int {
+	return (n + align - 1) / align * align
+}
+
+// columnStride returns the distance between the columns of a matrix with
+// the given rows, which are laid out as an array of vectors.
+func (l Layout) columnStride(rows int) int {
+	if l == Std140 {
+		return roundUp(vectorAlign(rows), 16)
+	}
+	return vectorAlign(rows)
+}
+
+// typeLayout returns the base alignment and size of t, appending the fields
+// it contains to fields.
+func (l Layout) typeLayout(t reflect.Type, name string, offset int, fields *[]blockField) (int, int, error) {
+	if bt, ok := blockTypes[t]; ok {
+		f := blockField{name: name, glType: bt.glType, offset: offset, size: 1}
+		align, size := vectorAlign(bt.rows), 4*bt.rows
+		if bt.columns > 1 {
+			f.matrixStride = l.columnStride(bt.rows)
+			align, size = f.matrixStride, f.matrixStride*bt.columns
+		}
+		if fields != nil {
+			*fields = append(*fields, f)
+		}
+		return align, size, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		return l.structLayout(t, name+".", offset, fields)
+
+	case reflect.Array:
+		elem := t.Elem()
+		if elem.Kind() == reflect.Array && blockTypes[elem].glType == 0 {
+			return 0, 0, fmt.Errorf("%s: arrays of arrays are not supported", name)
+		}
+		align, size, err := l.typeLayout(elem, name, 0, nil)
+		if err != nil {
+			return 0, 0, err
+		}
+		if l == Std140 {
+			align = roundUp(align, 16)
+		}
+		stride := roundUp(size, align)
+
+		if elem.Kind() == reflect.Struct {
+			// GL reports each element of an array of structs separately.
+			for i := 0; i < t.Len(); i++ {
+				if _, _, err := l.typeLayout(elem, fmt.Sprintf("%s[%d]", name, i), offset+i*stride, fields); err != nil {
+					return 0, 0, err
+				}
+			}
+		} else if fields != nil {
+			var f []blockField
+			l.typeLayout(elem, name+"[0]", offset, &f)
+			f[0].size = t.Len()
+			f[0].arrayStride = stride
+			*fields = append(*fields, f[0])
+		}
+		return align, stride * t.Len(), nil
+	}
+	return 0, 0, fmt.Errorf("%s: unsupported type %s", strings.TrimSuffix(name, "."), t)
+}
+
+// structLayout returns the base alignment and size of the struct t, placing
+// each field after the last at its base alignment.
+func (l Layout) structLayout(t reflect.Type, prefix string, offset int, fields *[]blockField) (int, int, error) {
+	align, size := 4, 0
+	if l == Std140 {
+		align = 16
+	}
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name := sf.Name
+		if tag := sf.Tag.Get("glsl"); tag != "" {
+			name = tag
+		}
+		fieldAlign, _, err := l.typeLayout(sf.Type, prefix+name, 0, nil)
+		if err != nil {
+			return 0, 0, err
+		}
+		size = roundUp(size, fieldAlign)
+		_, fieldSize, _ := l.typeLayout(sf.Type, prefix+name, offset+size, fields)
+		size += fieldSize
+		if fieldAlign > align {
+			align = fieldAlign
+		}
+	}
+	return align, roundUp(size, align), nil
+}
+
+// encode v into data at offset, following the same layout as typeLayout.
+func (l Layout) encode(v reflect.Value, data []byte, offset int) {
+	t := v.Type()
+	if bt, ok := blockTypes[t]; ok {
+		if bt.rows == 1 {
+			putScalar(v, data[offset:])
+			return
+		}
+		stride := 4 * bt.rows
+		if bt.columns > 1 {
+			stride = l.columnStride(bt.rows)
+		}
+		for c := 0; c < bt.columns; c++ {
+			for r := 0; r < bt.rows; r++ {
+				putScalar(v.Index(c*bt.rows+r), data[offset+c*stride+4*r:])
+			}
+		}
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		size := 0
+		for i := 0; i < t.NumField(); i++ {
+			align, fieldSize, _ := l.typeLayout(t.Field(i).Type, "", 0, nil)
+			size = roundUp(size, align)
+			l.encode(v.Field(i), data, offset+size)
+			size += fieldSize
+		}
+
+	case reflect.Array:
+		align, size, _ := l.typeLayout(t.Elem(), "", 0, nil)
+		if l == Std140 {
+			align = roundUp(align, 16)
+		}
+		stride := roundUp(size, align)
+		for i := 0; i < v.Len(); i++ {
+			l.encode(v.Index(i), data, offset+i*stride)
+		}
+	}
+}
+
+// putScalar writes a float32, int32, uint32 or bool to data.
+func putScalar(v reflect.Value, data []byte) {
+	var bits uint32
+	switch v.Kind() {
+	case reflect.Float32:
+		bits = math.Float32bits(float32(v.Float()))
+	case reflect.Int32:
+		bits = uint32(v.Int())
+	case reflect.Uint32:
+		bits = uint32(v.Uint())
+	case reflect.Bool:
+		if v.Bool() {
+			bits = 1
+		}
+	}
+	binary.LittleEndian.PutUint32(data, bits)
+}
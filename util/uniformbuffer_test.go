@@ -0,0 +1,307 @@
+package util
+
+import (
+	"encoding/binary"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Structs laid out by TestStructLayout.
+type (
+	vec3Float struct {
+		A mgl32.Vec3
+		B float32
+	}
+	floatArray struct {
+		A [3]float32
+		B float32
+	}
+	vec2Array struct {
+		A [2]mgl32.Vec2
+		B float32
+	}
+	matrices3 struct {
+		A mgl32.Mat3
+		B float32
+		C mgl32.Mat2
+		D float32
+	}
+	pair struct {
+		A, B float32
+	}
+	nestedPair struct {
+		X float32
+		P pair `glsl:"pair"`
+		Y float32
+	}
+	light struct {
+		Position  mgl32.Vec3 `glsl:"position"`
+		Intensity float32    `glsl:"intensity"`
+	}
+	lights struct {
+		Ambient float32  `glsl:"ambient"`
+		Sun     light    `glsl:"sun"`
+		Lights  [2]light `glsl:"lights"`
+		Count   int32    `glsl:"count"`
+	}
+)
+
+func TestStructLayout(t *testing.T) {
+	tests := []struct {
+		name   string
+		v      interface{}
+		layout Layout
+		want   []blockField
+		size   int
+	}{
+		// A vec3 is aligned to 16 bytes but only 12 long, so a scalar fits
+		// after it.
+		{
+			name:   "vec3 then float std140",
+			v:      vec3Float{},
+			layout: Std140,
+			want: []blockField{
+				{name: "A", glType: gl.FLOAT_VEC3, offset: 0, size: 1},
+				{name: "B", glType: gl.FLOAT, offset: 12, size: 1},
+			},
+			size: 16,
+		},
+		{
+			name:   "vec3 then float std430",
+			v:      vec3Float{},
+			layout: Std430,
+			want: []blockField{
+				{name: "A", glType: gl.FLOAT_VEC3, offset: 0, size: 1},
+				{name: "B", glType: gl.FLOAT, offset: 12, size: 1},
+			},
+			size: 16,
+		},
+		// std140 rounds the stride of scalar and vec2 arrays up to a vec4.
+		{
+			name:   "float array std140",
+			v:      floatArray{},
+			layout: Std140,
+			want: []blockField{
+				{name: "A[0]", glType: gl.FLOAT, offset: 0, size: 3, arrayStride: 16},
+				{name: "B", glType: gl.FLOAT, offset: 48, size: 1},
+			},
+			size: 64,
+		},
+		{
+			name:   "float array std430",
+			v:      floatArray{},
+			layout: Std430,
+			want: []blockField{
+				{name: "A[0]", glType: gl.FLOAT, offset: 0, size: 3, arrayStride: 4},
+				{name: "B", glType: gl.FLOAT, offset: 12, size: 1},
+			},
+			size: 16,
+		},
+		{
+			name:   "vec2 array std140",
+			v:      vec2Array{},
+			layout: Std140,
+			want: []blockField{
+				{name: "A[0]", glType: gl.FLOAT_VEC2, offset: 0, size: 2, arrayStride: 16},
+				{name: "B", glType: gl.FLOAT, offset: 32, size: 1},
+			},
+			size: 48,
+		},
+		{
+			name:   "vec2 array std430",
+			v:      vec2Array{},
+			layout: Std430,
+			want: []blockField{
+				{name: "A[0]", glType: gl.FLOAT_VEC2, offset: 0, size: 2, arrayStride: 8},
+				{name: "B", glType: gl.FLOAT, offset: 16, size: 1},
+			},
+			size: 24,
+		},
+		// Matrices are arrays of column vectors, so a mat3 has a column
+		// stride of 16 in both layouts, and a mat2 only packs in std430.
+		{
+			name:   "matrices std140",
+			v:      matrices3{},
+			layout: Std140,
+			want: []blockField{
+				{name: "A", glType: gl.FLOAT_MAT3, offset: 0, size: 1, matrixStride: 16},
+				{name: "B", glType: gl.FLOAT, offset: 48, size: 1},
+				{name: "C", glType: gl.FLOAT_MAT2, offset: 64, size: 1, matrixStride: 16},
+				{name: "D", glType: gl.FLOAT, offset: 96, size: 1},
+			},
+			size: 112,
+		},
+		{
+			name:   "matrices std430",
+			v:      matrices3{},
+			layout: Std430,
+			want: []blockField{
+				{name: "A", glType: gl.FLOAT_MAT3, offset: 0, size: 1, matrixStride: 16},
+				{name: "B", glType: gl.FLOAT, offset: 48, size: 1},
+				{name: "C", glType: gl.FLOAT_MAT2, offset: 56, size: 1, matrixStride: 8},
+				{name: "D", glType: gl.FLOAT, offset: 72, size: 1},
+			},
+			size: 80,
+		},
+		// std140 aligns structs to a vec4 and pads their size to match.
+		{
+			name:   "nested struct std140",
+			v:      nestedPair{},
+			layout: Std140,
+			want: []blockField{
+				{name: "X", glType: gl.FLOAT, offset: 0, size: 1},
+				{name: "pair.A", glType: gl.FLOAT, offset: 16, size: 1},
+				{name: "pair.B", glType: gl.FLOAT, offset: 20, size: 1},
+				{name: "Y", glType: gl.FLOAT, offset: 32, size: 1},
+			},
+			size: 48,
+		},
+		{
+			name:   "nested struct std430",
+			v:      nestedPair{},
+			layout: Std430,
+			want: []blockField{
+				{name: "X", glType: gl.FLOAT, offset: 0, size: 1},
+				{name: "pair.A", glType: gl.FLOAT, offset: 4, size: 1},
+				{name: "pair.B", glType: gl.FLOAT, offset: 8, size: 1},
+				{name: "Y", glType: gl.FLOAT, offset: 12, size: 1},
+			},
+			size: 16,
+		},
+		{
+			name:   "array of structs std140",
+			v:      lights{},
+			layout: Std140,
+			want: []blockField{
+				{name: "ambient", glType: gl.FLOAT, offset: 0, size: 1},
+				{name: "sun.position", glType: gl.FLOAT_VEC3, offset: 16, size: 1},
+				{name: "sun.intensity", glType: gl.FLOAT, offset: 28, size: 1},
+				{name: "lights[0].position", glType: gl.FLOAT_VEC3, offset: 32, size: 1},
+				{name: "lights[0].intensity", glType: gl.FLOAT, offset: 44, size: 1},
+				{name: "lights[1].position", glType: gl.FLOAT_VEC3, offset: 48, size: 1},
+				{name: "lights[1].intensity", glType: gl.FLOAT, offset: 60, size: 1},
+				{name: "count", glType: gl.INT, offset: 64, size: 1},
+			},
+			size: 80,
+		},
+		{
+			name:   "array of structs std430",
+			v:      lights{},
+			layout: Std430,
+			want: []blockField{
+				{name: "ambient", glType: gl.FLOAT, offset: 0, size: 1},
+				{name: "sun.position", glType: gl.FLOAT_VEC3, offset: 16, size: 1},
+				{name: "sun.intensity", glType: gl.FLOAT, offset: 28, size: 1},
+				{name: "lights[0].position", glType: gl.FLOAT_VEC3, offset: 32, size: 1},
+				{name: "lights[0].intensity", glType: gl.FLOAT, offset: 44, size: 1},
+				{name: "lights[1].position", glType: gl.FLOAT_VEC3, offset: 48, size: 1},
+				{name: "lights[1].intensity", glType: gl.FLOAT, offset: 60, size: 1},
+				{name: "count", glType: gl.INT, offset: 64, size: 1},
+			},
+			size: 80,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fields []blockField
+			_, size, err := tt.layout.structLayout(reflect.TypeOf(tt.v), "", 0, &fields)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(fields, tt.want) {
+				t.Errorf("fields =\n%+v\nwant\n%+v", fields, tt.want)
+			}
+			if size != tt.size {
+				t.Errorf("size = %d, want %d", size, tt.size)
+			}
+		})
+	}
+}
+
+func TestStructLayoutUnsupported(t *testing.T) {
+	for _, v := range []interface{}{
+		struct{ A float64 }{},
+		struct{ A [2][2]float32 }{},
+	} {
+		if _, _, err := Std140.structLayout(reflect.TypeOf(v), "", 0, nil); err == nil {
+			t.Errorf("%T: no error", v)
+		}
+	}
+}
+
+func TestEncode(t *testing.T) {
+	v := struct {
+		A mgl32.Vec3
+		B float32
+		C [2]float32
+		D mgl32.Mat2
+		E pair
+		F bool
+	}{
+		A: mgl32.Vec3{1, 2, 3},
+		B: 4,
+		C: [2]float32{5, 6},
+		D: mgl32.Mat2{7, 8, 9, 10},
+		E: pair{11, 12},
+		F: true,
+	}
+
+	tests := []struct {
+		layout Layout
+		// want maps byte offsets to the float32 stored there.
+		want map[int]float32
+		// bool is the offset of F.
+		bool int
+		size int
+	}{
+		{
+			layout: Std140,
+			want: map[int]float32{
+				0: 1, 4: 2, 8: 3, 12: 4,
+				16: 5, 32: 6,
+				48: 7, 52: 8, 64: 9, 68: 10,
+				80: 11, 84: 12,
+			},
+			bool: 96,
+			size: 112,
+		},
+		{
+			layout: Std430,
+			want: map[int]float32{
+				0: 1, 4: 2, 8: 3, 12: 4,
+				16: 5, 20: 6,
+				24: 7, 28: 8, 32: 9, 36: 10,
+				40: 11, 44: 12,
+			},
+			bool: 48,
+			size: 64,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.layout.String(), func(t *testing.T) {
+			_, size, err := tt.layout.structLayout(reflect.TypeOf(v), "", 0, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if size != tt.size {
+				t.Fatalf("size = %d, want %d", size, tt.size)
+			}
+			data := make([]byte, size)
+			tt.layout.encode(reflect.ValueOf(v), data, 0)
+			for offset, want := range tt.want {
+				if got := math.Float32frombits(binary.LittleEndian.Uint32(data[offset:])); got != want {
+					t.Errorf("offset %d = %g, want %g", offset, got, want)
+				}
+			}
+			if got := binary.LittleEndian.Uint32(data[tt.bool:]); got != 1 {
+				t.Errorf("bool at offset %d = %d, want 1", tt.bool, got)
+			}
+		})
+	}
+}
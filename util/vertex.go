@@ -0,0 +1,200 @@
+package util
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// VertexLayout describes the attributes of a vertex struct, so the
+// matching gl.VertexAttribPointer calls can be made from the struct rather
+// than from strides and offsets worked out by hand.
+//
+// Fields are mapped to attributes with a vertex tag giving the location,
+// optionally followed by normalized, for integers read as floats in [0, 1]
+// or [-1, 1], or integer, for integers read as ints by the shader:
+//
+//	type vertex struct {
+//		Pos   mgl32.Vec2 `vertex:"location=0"`
+//		Color [4]uint8   `vertex:"location=1,normalized"`
+//	}
+//
+// Fields may be float32, int8, uint8, int16, uint16, int32 or uint32, or
+// arrays of 1 to 4 of them such as mgl32.Vec3.  Fields without a tag are
+// skipped, but still count towards the stride.
+type VertexLayout struct {
+	// Stride in bytes between vertices in an interleaved buffer.
+	Stride int
+	// Attributes in field order.
+	Attributes []VertexAttrib
+}
+
+// VertexAttrib is a single attribute of a vertex.
+type VertexAttrib struct {
+	// Name of the struct field.
+	Name     string
+	Location uint32
+	// Size is the number of components, 1 to 4.
+	Size int32
+	// Type of each component, such as gl.FLOAT.
+	Type uint32
+	// Normalized integers are converted to floats in [0, 1] or [-1, 1].
+	Normalized bool
+	// Integer attributes are passed to the shader as ints.
+	Integer bool
+	// Offset of the field within the struct.
+	Offset int
+	// bytes taken by the attribute.
+	bytes int
+}
+
+// vertexTypes for each kind of component, with their size in bytes.
+var vertexTypes = map[reflect.Kind]struct {
+	glType uint32
+	size   int
+}{
+	reflect.Float32: {gl.FLOAT, 4},
+	reflect.Int8:    {gl.BYTE, 1},
+	reflect.Uint8:   {gl.UNSIGNED_BYTE, 1},
+	reflect.Int16:   {gl.SHORT, 2},
+	reflect.Uint16:  {gl.UNSIGNED_SHORT, 2},
+	reflect.Int32:   {gl.INT, 4},
+	reflect.Uint32:  {gl.UNSIGNED_INT, 4},
+}
+
+// NewVertexLayout returns the layout of vertex, which must be a struct or a
+// pointer to one.  Attributes must start on a 4 byte boundary, as required
+// by many drivers, so a layout which would leave one misaligned is rejected
+// rather than rendering garbage.
+func NewVertexLayout(vertex interface{}) (*VertexLayout, error) {
+	t := reflect.TypeOf(vertex)
+	if t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("vertex must be a struct, not %v", t)
+	}
+
+	l := &VertexLayout{Stride: int(t.Size())}
+	if l.Stride%4 != 0 {
+		return nil, fmt.Errorf("%s: stride of %d bytes is not a multiple of 4", t, l.Stride)
+	}
+	locations := map[uint32]string{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, ok := f.Tag.Lookup("vertex")
+		if !ok || tag == "-" {
+			continue
+		}
+		a, err := newVertexAttrib(f, tag)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %s", t, f.Name, err)
+		}
+		if other, ok := locations[a.Location]; ok {
+			return nil, fmt.Errorf("%s.%s: location %d is already used by %s", t, f.Name, a.Location, other)
+		}
+		locations[a.Location] = f.Name
+		l.Attributes = append(l.Attributes, a)
+	}
+	if len(l.Attributes) == 0 {
+		return nil, fmt.Errorf("%s has no fields with a vertex tag", t)
+	}
+	return l, nil
+}
+
+// newVertexAttrib parses the tag of field f.
+func newVertexAttrib(f reflect.StructField, tag string) (VertexAttrib, error) {
+	a := VertexAttrib{Name: f.Name, Offset: int(f.Offset), Size: 1}
+
+	t := f.Type
+	if t.Kind() == reflect.Array {
+		if t.Len() < 1 || t.Len() > 4 {
+			return a, fmt.Errorf("%s has %d components, attributes have 1 to 4", t, t.Len())
+		}
+		a.Size = int32(t.Len())
+		t = t.Elem()
+	}
+	vt, ok := vertexTypes[t.Kind()]
+	if !ok {
+		return a, fmt.Errorf("unsupported type %s", f.Type)
+	}
+	a.Type = vt.glType
+	a.bytes = vt.size * int(a.Size)
+
+	location := false
+	for _, opt := range strings.Split(tag, ",") {
+		switch opt = strings.TrimSpace(opt); {
+		case strings.HasPrefix(opt, "location="):
+			n, err := strconv.ParseUint(strings.TrimPrefix(opt, "location="), 10, 32)
+			if err != nil {
+				return a, fmt.Errorf("bad location in tag %q", tag)
+			}
+			a.Location = uint32(n)
+			location = true
+		case opt == "normalized":
+			a.Normalized = true
+		case opt == "integer":
+			a.Integer = true
+		default:
+			return a, fmt.Errorf("unknown option %q in tag %q", opt, tag)
+		}
+	}
+
+	switch {
+	case !location:
+		return a, fmt.Errorf("tag %q has no location", tag)
+	case (a.Normalized || a.Integer) && a.Type == gl.FLOAT:
+		return a, fmt.Errorf("normalized and integer only apply to integer types")
+	case a.Normalized && a.Integer:
+		return a, fmt.Errorf("an attribute can not be both normalized and integer")
+	case a.Offset%4 != 0:
+		return a, fmt.Errorf("offset %d is not a multiple of 4", a.Offset)
+	}
+	return a, nil
+}
+
+// Interleaved sets up every attribute to be read from buffer, which holds
+// an array of the vertex struct.  The VAO to set up must be bound.
+func (l *VertexLayout) Interleaved(buffer uint32) {
+	gl.BindBuffer(gl.ARRAY_BUFFER, buffer)
+	for _, a := range l.Attributes {
+		a.pointer(int32(l.Stride), a.Offset)
+	}
+}
+
+// Separate sets up each attribute to be read from its own tightly packed
+// array, attribute i starting at offsets[i] in buffers[i].  The same buffer
+// may be given for several attributes.  The VAO to set up must be bound.
+func (l *VertexLayout) Separate(buffers []uint32, offsets []int) error {
+	if len(buffers) != len(l.Attributes) || len(offsets) != len(l.Attributes) {
+		return fmt.Errorf("layout has %d attributes, got %d buffers and %d offsets", len(l.Attributes), len(buffers), len(offsets))
+	}
+	for i, a := range l.Attributes {
+		if offsets[i]%4 != 0 {
+			return fmt.Errorf("%s: offset %d is not a multiple of 4", a.Name, offsets[i])
+		}
+		if a.bytes%4 != 0 {
+			return fmt.Errorf("%s: stride of %d bytes is not a multiple of 4", a.Name, a.bytes)
+		}
+	}
+
+	for i, a := range l.Attributes {
+		gl.BindBuffer(gl.ARRAY_BUFFER, buffers[i])
+		a.pointer(0, offsets[i])
+	}
+	return nil
+}
+
+// pointer points the attribute at offset in the bound array buffer, and
+// enables it.
+func (a VertexAttrib) pointer(stride int32, offset int) {
+	if a.Integer {
+		gl.VertexAttribIPointer(a.Location, a.Size, a.Type, stride, gl.PtrOffset(offset))
+	} else {
+		gl.VertexAttribPointer(a.Location, a.Size, a.Type, a.Normalized, stride, gl.PtrOffset(offset))
+	}
+	gl.EnableVertexAttribArray(a.Location)
+}
@@ -0,0 +1,176 @@
+package util
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestNewVertexLayout(t *testing.T) {
+	type colored struct {
+		Pos   [2]float32 `vertex:"location=0"`
+		Color [4]uint8   `vertex:"location=1,normalized"`
+	}
+	type mixed struct {
+		Pos     mgl32.Vec3 `vertex:"location=0"`
+		Pad     float32
+		Skipped float32   `vertex:"-"`
+		ID      int32     `vertex:"location=2,integer"`
+		Weights [2]uint16 `vertex:"location=3, normalized"`
+		Offset  [2]int16  `vertex:"location=4"`
+	}
+
+	tests := []struct {
+		name   string
+		vertex interface{}
+		want   *VertexLayout
+		// wantErr is part of the expected error, if any.
+		wantErr string
+	}{
+		{
+			name:   "normalized color",
+			vertex: colored{},
+			want: &VertexLayout{
+				Stride: 12,
+				Attributes: []VertexAttrib{
+					{Name: "Pos", Location: 0, Size: 2, Type: gl.FLOAT, Offset: 0, bytes: 8},
+					{Name: "Color", Location: 1, Size: 4, Type: gl.UNSIGNED_BYTE, Normalized: true, Offset: 8, bytes: 4},
+				},
+			},
+		},
+		{
+			name:   "pointer to struct",
+			vertex: &colored{},
+			want: &VertexLayout{
+				Stride: 12,
+				Attributes: []VertexAttrib{
+					{Name: "Pos", Location: 0, Size: 2, Type: gl.FLOAT, Offset: 0, bytes: 8},
+					{Name: "Color", Location: 1, Size: 4, Type: gl.UNSIGNED_BYTE, Normalized: true, Offset: 8, bytes: 4},
+				},
+			},
+		},
+		{
+			name:   "skipped fields count towards offsets",
+			vertex: mixed{},
+			want: &VertexLayout{
+				Stride: 32,
+				Attributes: []VertexAttrib{
+					{Name: "Pos", Location: 0, Size: 3, Type: gl.FLOAT, Offset: 0, bytes: 12},
+					{Name: "ID", Location: 2, Size: 1, Type: gl.INT, Integer: true, Offset: 20, bytes: 4},
+					{Name: "Weights", Location: 3, Size: 2, Type: gl.UNSIGNED_SHORT, Normalized: true, Offset: 24, bytes: 4},
+					{Name: "Offset", Location: 4, Size: 2, Type: gl.SHORT, Offset: 28, bytes: 4},
+				},
+			},
+		},
+		{
+			name:    "not a struct",
+			vertex:  []float32{},
+			wantErr: "vertex must be a struct",
+		},
+		{
+			name:    "nil",
+			vertex:  nil,
+			wantErr: "vertex must be a struct",
+		},
+		{
+			name: "stride",
+			vertex: struct {
+				Color [3]uint8 `vertex:"location=0,normalized"`
+			}{},
+			wantErr: "stride of 3 bytes is not a multiple of 4",
+		},
+		{
+			name: "misaligned",
+			vertex: struct {
+				Flag  uint8
+				Color [3]uint8 `vertex:"location=0,normalized"`
+			}{},
+			wantErr: "Color: offset 1 is not a multiple of 4",
+		},
+		{
+			name: "location used twice",
+			vertex: struct {
+				A float32 `vertex:"location=1"`
+				B float32 `vertex:"location=1"`
+			}{},
+			wantErr: "B: location 1 is already used by A",
+		},
+		{
+			name: "no location",
+			vertex: struct {
+				A [4]uint8 `vertex:"normalized"`
+			}{},
+			wantErr: "has no location",
+		},
+		{
+			name: "bad location",
+			vertex: struct {
+				A float32 `vertex:"location=x"`
+			}{},
+			wantErr: "bad location",
+		},
+		{
+			name: "unknown option",
+			vertex: struct {
+				A float32 `vertex:"location=0,flat"`
+			}{},
+			wantErr: `unknown option "flat"`,
+		},
+		{
+			name: "normalized float",
+			vertex: struct {
+				A float32 `vertex:"location=0,normalized"`
+			}{},
+			wantErr: "only apply to integer types",
+		},
+		{
+			name: "normalized integer",
+			vertex: struct {
+				A int32 `vertex:"location=0,normalized,integer"`
+			}{},
+			wantErr: "both normalized and integer",
+		},
+		{
+			name: "too many components",
+			vertex: struct {
+				A [5]float32 `vertex:"location=0"`
+			}{},
+			wantErr: "has 5 components",
+		},
+		{
+			name: "unsupported type",
+			vertex: struct {
+				A float64 `vertex:"location=0"`
+			}{},
+			wantErr: "unsupported type float64",
+		},
+		{
+			name: "no attributes",
+			vertex: struct {
+				A float32
+			}{},
+			wantErr: "has no fields with a vertex tag",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewVertexLayout(tt.vertex)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("layout =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-gl/glfw/v3.1/glfw"
+)
+
+// Disabled turns off a WindowOptions setting whose zero value selects the
+// default, such as vsync or the depth buffer.
+const Disabled = -1
+
+// Profile of the GL context.
+type Profile int
+
+// Context profiles.  The bindings are for the core profile, so the
+// compatibility profile only adds the deprecated functions to the context.
+const (
+	CoreProfile Profile = iota
+	CompatProfile
+)
+
+// String returns the profile as a word.
+func (p Profile) String() string {
+	if p == CompatProfile {
+		return "compatibility"
+	}
+	return "core"
+}
+
+// GLVersion of a context.
+type GLVersion struct {
+	Major int `json:"major"`
+	Minor int `json:"minor"`
+}
+
+// String formats the version as major.minor.
+func (v GLVersion) String() string {
+	return fmt.Sprintf("%d.%d", v.Major, v.Minor)
+}
+
+// Less reports whether v is an earlier version than w.
+func (v GLVersion) Less(w GLVersion) bool {
+	return v.Major < w.Major || v.Major == w.Major && v.Minor < w.Minor
+}
+
+// glVersions the bindings can run on, newest first.  4.1 is both the oldest
+// the bindings load on and the newest macOS supports.
+var glVersions = []GLVersion{{4, 6}, {4, 5}, {4, 4}, {4, 3}, {4, 2}, {4, 1}}
+
+// WindowOptions used by NewWindow and NewHeadless.  The zero value, apart
+// from the size, is a fixed size window with a GL 4.1 core context which
+// syncs to the display.
+type WindowOptions struct {
+	// Title displayed in the title bar.
+	Title string
+	// Width and Height of the window, or the screen resolution when
+	// fullscreen.  0 uses the monitor's current mode when fullscreen.
+	Width, Height int
+	// Resizable lets the user resize the window.
+	Resizable bool
+	// Hidden creates the window without showing it, for when only the
+	// context is wanted.
+	Hidden bool
+	// Fullscreen opens the window fullscreen on Monitor.
+	Fullscreen bool
+	// Monitor to go fullscreen on, as an index into glfw.GetMonitors.  0 is
+	// the primary monitor.
+	Monitor int
+	// SwapInterval is the number of screen updates to wait for before
+	// swapping buffers, 1 if 0.  Disabled swaps as soon as possible.
+	SwapInterval int
+	// Samples per pixel for multisampling, 0 turns it off.
+	Samples int
+	// SRGB requests an sRGB capable framebuffer, and enables conversion to
+	// sRGB when writing to it.
+	SRGB bool
+	// DepthBits and StencilBits of the depth and stencil buffers, 24 and 8
+	// if 0.  Disabled leaves the buffer out.
+	DepthBits, StencilBits int
+	// Version of GL to request, 4.1 if zero.
+	Version GLVersion
+	// Profile of the context to request.
+	Profile Profile
+	// Fallback versions tried in order when Version is not available.  If
+	// nil every version older than Version down to 4.1 is tried.
+	Fallback []GLVersion
+}
+
+// versions returns the GL versions to try creating a context with, in
+// order.
+func (o WindowOptions) versions() []GLVersion {
+	want := o.Version
+	if want == (GLVersion{}) {
+		want = GLVersion{4, 1}
+	}
+	versions := []GLVersion{want}
+	if o.Fallback != nil {
+		return append(versions, o.Fallback...)
+	}
+	for _, v := range glVersions {
+		if v.Less(want) {
+			versions = append(versions, v)
+		}
+	}
+	return versions
+}
+
+// bits returns the hint for a depth or stencil size.
+func bits(n, def int) int {
+	switch n {
+	case 0:
+		return def
+	case Disabled:
+		return 0
+	}
+	return n
+}
+
+// glfwBool converts b to glfw.True or glfw.False.
+func glfwBool(b bool) int {
+	if b {
+		return glfw.True
+	}
+	return glfw.False
+}
+
+// monitor returns the monitor to go fullscreen on.
+func (o WindowOptions) monitor() (*glfw.Monitor, error) {
+	if o.Monitor == 0 {
+		return glfw.GetPrimaryMonitor(), nil
+	}
+	monitors := glfw.GetMonitors()
+	if o.Monitor < 0 || o.Monitor >= len(monitors) {
+		return nil, fmt.Errorf("no monitor %d, there are %d", o.Monitor, len(monitors))
+	}
+	return monitors[o.Monitor], nil
+}
+
+// createWindow tries each version in turn until a window can be created
+// with a context of that version.
+func createWindow(opts WindowOptions) (*glfw.Window, GLVersion, error) {
+	glfw.DefaultWindowHints()
+	glfw.WindowHint(glfw.Resizable, glfwBool(opts.Resizable))
+	glfw.WindowHint(glfw.Visible, glfwBool(!opts.Hidden))
+	glfw.WindowHint(glfw.Samples, opts.Samples)
+	glfw.WindowHint(glfw.SRGBCapable, glfwBool(opts.SRGB))
+	glfw.WindowHint(glfw.DepthBits, bits(opts.DepthBits, 24))
+	glfw.WindowHint(glfw.StencilBits, bits(opts.StencilBits, 8))
+	if opts.Profile == CompatProfile {
+		glfw.WindowHint(glfw.OpenGLProfile, glfw.OpenGLCompatProfile)
+	} else {
+		glfw.WindowHint(glfw.OpenGLProfile, glfw.OpenGLCoreProfile)
+		glfw.WindowHint(glfw.OpenGLForwardCompatible, glfw.True)
+	}
+	if debug.context {
+		glfw.WindowHint(glfw.OpenGLDebugContext, glfw.True)
+	}
+
+	width, height := opts.Width, opts.Height
+	var monitor *glfw.Monitor
+	if opts.Fullscreen {
+		var err error
+		if monitor, err = opts.monitor(); err != nil {
+			return nil, GLVersion{}, err
+		}
+		if mode := monitor.GetVideoMode(); mode != nil {
+			glfw.WindowHint(glfw.RefreshRate, mode.RefreshRate)
+			if width == 0 || height == 0 {
+				width, height = mode.Width, mode.Height
+			}
+		}
+	}
+
+	var errs []string
+	for _, v := range opts.versions() {
+		glfw.WindowHint(glfw.ContextVersionMajor, v.Major)
+		glfw.WindowHint(glfw.ContextVersionMinor, v.Minor)
+		window, err := glfw.CreateWindow(width, height, opts.Title, monitor, nil)
+		if err == nil {
+			return window, v, nil
+		}
+		errs = append(errs, fmt.Sprintf("GL %s %s: %s", v, opts.Profile, err))
+	}
+	return nil, GLVersion{}, fmt.Errorf("failed to create window: %s", strings.Join(errs, "; "))
+}